@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022-2023 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -15,6 +15,9 @@ import (
 type TupleGenerationJobState string
 
 const (
+	// JobQueued means that the job waits for being admitted as the maximum number of concurrent jobs has been reached.
+	JobQueued TupleGenerationJobState = "Queued"
+
 	// JobPending means that not all tasks of the job have been spawned yet.
 	JobPending TupleGenerationJobState = "Pending"
 
@@ -26,12 +29,15 @@ const (
 
 	// JobFailed means that all tasks for the job have terminated but at least on failed.
 	JobFailed TupleGenerationJobState = "Failed"
+
+	// JobCancelled means that the job has been cancelled on request before it completed.
+	JobCancelled TupleGenerationJobState = "Cancelled"
 )
 
 // IsValid returns true if state s is among the defined ones and false otherwise.
 func (s TupleGenerationJobState) IsValid() bool {
 	switch s {
-	case JobPending, JobRunning, JobCompleted, JobFailed:
+	case JobQueued, JobPending, JobRunning, JobCompleted, JobFailed, JobCancelled:
 		return true
 	default:
 		return false
@@ -39,9 +45,75 @@ func (s TupleGenerationJobState) IsValid() bool {
 }
 
 // IsDone returns true if s is among the set of TupleGenerationJobState that describe a job that is done, i.e., is
-// either JobCompleted or JobFailed, and false otherwise.
+// either JobCompleted, JobFailed, or JobCancelled, and false otherwise.
 func (s TupleGenerationJobState) IsDone() bool {
-	return s == JobCompleted || s == JobFailed
+	return s == JobCompleted || s == JobFailed || s == JobCancelled
+}
+
+// JobRetryPolicy specifies how failed jobs are retried. A retry is a new job generating the same number of tuples of
+// the same type using a fresh tuple chunk identifier, as the tuple chunk of the failed job might have been partially
+// uploaded to Castor already. Retries are delayed by an exponentially growing backoff.
+type JobRetryPolicy struct {
+
+	// MaxRetries is the maximum number of times a job is retried.
+	//+kubebuilder:default=3
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetries int `json:"maxRetries,omitempty"`
+
+	// InitialBackoffSeconds is the time between the failure of a job and its first retry. The backoff doubles with
+	// each subsequent retry.
+	//+kubebuilder:default=30
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	InitialBackoffSeconds int `json:"initialBackoffSeconds,omitempty"`
+
+	// MaxBackoffSeconds is the upper bound for the backoff.
+	//+kubebuilder:default=600
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBackoffSeconds int `json:"maxBackoffSeconds,omitempty"`
+}
+
+// TaskTimeouts specifies how long the tasks of a job may stay in the individual states before they are considered to
+// be stuck and fail. A timeout of zero disables the timeout for the respective state.
+type TaskTimeouts struct {
+
+	// PreparingSeconds bounds the time for preparing a task, including waiting for the endpoints of all VCPs.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	PreparingSeconds int `json:"preparingSeconds,omitempty"`
+
+	// LaunchingSeconds bounds the time for launching the generator of a task.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	LaunchingSeconds int `json:"launchingSeconds,omitempty"`
+
+	// GeneratingSeconds bounds the time for generating the tuples of a task.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	GeneratingSeconds int `json:"generatingSeconds,omitempty"`
+
+	// ProvisioningSeconds bounds the time for uploading the tuples of a task to Castor.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	ProvisioningSeconds int `json:"provisioningSeconds,omitempty"`
+}
+
+// JobShardingSpec specifies how a job is split into shards. Each shard is a job of its own generating a share of the
+// tuples of the sharded job using a separate set of tasks and its own tuple chunk identifier.
+type JobShardingSpec struct {
+
+	// Shards is the number of shards the job is split into. The tuples are distributed evenly across the shards. A job
+	// is split into at most as many shards as it generates tuples.
+	//+kubebuilder:validation:Minimum=1
+	Shards int `json:"shards"`
+
+	// MaxParallelShards bounds the number of shards of the job running at the same time. All shards run in parallel in
+	// case not given.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	MaxParallelShards int `json:"maxParallelShards,omitempty"`
 }
 
 // TupleGenerationJobSpec defines the desired state of a TupleGenerationJob.
@@ -61,12 +133,189 @@ type TupleGenerationJobSpec struct {
 
 	// Generator is the name of the TupleGenerator that should be used for tuple generation by this job.
 	Generator string `json:"generatorRef"`
+
+	// RetryPolicy specifies whether and how the job is retried in case it fails. Jobs are not retried in case not
+	// given.
+	// +optional
+	RetryPolicy *JobRetryPolicy `json:"retryPolicy,omitempty"`
+
+	// ActiveDeadlineSeconds is the time relative to the start of the job on the coordinating VCP after which the job
+	// fails on all VCPs in case it is not done yet. Time spent queued before the job has been admitted doesn't count
+	// against the deadline. The job has no deadline in case not given.
+	//+kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// TaskTimeouts bounds the time the tasks of the job may stay in the individual states.
+	// +optional
+	TaskTimeouts *TaskTimeouts `json:"taskTimeouts,omitempty"`
+
+	// Priority determines the order in which queued jobs are admitted. Jobs with a higher priority are admitted first.
+	// Jobs of the same priority are admitted in the order they have been created.
+	// +optional
+	Priority int `json:"priority,omitempty"`
+
+	// Sharding splits the job into shards generating tuples in parallel. The job is not split in case not given.
+	// +optional
+	Sharding *JobShardingSpec `json:"sharding,omitempty"`
+
+	// Cancel requests the job to be cancelled on all VCPs. Cancelling a job that is done already has no effect, except
+	// that a failed job is not retried anymore.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+const (
+	// JobDeadlineExceeded is the reason for a job failing because it did not finish within its active deadline.
+	JobDeadlineExceeded = "DeadlineExceeded"
+
+	// JobTaskFailed is the reason for a job failing because at least one of its tasks failed.
+	JobTaskFailed = "TaskFailed"
+
+	// JobCancellationRequested is the reason for a job having been cancelled on request.
+	JobCancellationRequested = "CancellationRequested"
+
+	// JobActivationFailed is the reason for a job failing because the generated tuple chunk could not be activated in
+	// Castor within the maximum number of attempts.
+	JobActivationFailed = "ActivationFailed"
+
+	// JobShardFailed is the reason for a sharded job failing because at least one of its shards failed and is not
+	// retried anymore or has been cancelled.
+	JobShardFailed = "ShardFailed"
+)
+
+const (
+	// JobTasksSpawned is the type of the condition signalling whether the tasks of a job have been spawned on all VCPs.
+	JobTasksSpawned = "TasksSpawned"
+
+	// JobTupleChunkActivated is the type of the condition signalling whether the tuple chunk generated by a job has been
+	// activated in Castor.
+	JobTupleChunkActivated = "TupleChunkActivated"
+
+	// JobFinished is the type of the condition signalling whether a job is done, i.e., either completed, failed, or
+	// cancelled.
+	JobFinished = "Finished"
+
+	// JobShardsCreated is the type of the condition signalling whether all shards of a sharded job have been created.
+	JobShardsCreated = "ShardsCreated"
+)
+
+// TaskSummary summarizes the state of the task of a job on a single VCP.
+type TaskSummary struct {
+
+	// PlayerID is the zero-based identifier of the VCP executing the task.
+	PlayerID uint `json:"playerId"`
+
+	// State is the state of the task.
+	State TupleGenerationTaskState `json:"state"`
+
+	// Endpoint is the endpoint exposed by the VCP for inter-CRG communication, if available.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Reason is a machine-readable CamelCase reason for the task having failed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ShardStatus summarizes the state of a shard of a sharded job.
+type ShardStatus struct {
+
+	// Index is the zero-based index of the shard.
+	Index int `json:"index"`
+
+	// Job is the name of the job generating the shard, i.e., the most recent retry in case the shard has been retried.
+	Job string `json:"job"`
+
+	// ID is the identifier of the tuple chunk generated by the shard.
+	ID string `json:"id"`
+
+	// Count is the number of tuples generated by the shard.
+	Count int `json:"count"`
+
+	// State is the state of the job generating the shard.
+	// +optional
+	State TupleGenerationJobState `json:"state,omitempty"`
+}
+
+// TupleChunkActivationStatus describes the result of activating the tuple chunk generated by a job in Castor. Failed
+// activations are retried with backoff up to a maximum number of attempts.
+type TupleChunkActivationStatus struct {
+
+	// Activated tells whether the tuple chunk has been activated.
+	Activated bool `json:"activated"`
+
+	// Time is the point in time of the most recent activation attempt.
+	Time metav1.Time `json:"time"`
+
+	// Attempts is the number of activation attempts made so far.
+	// +optional
+	Attempts int `json:"attempts,omitempty"`
+
+	// NextAttemptTime is the point in time the activation is retried after it failed. Not set in case the maximum
+	// number of attempts has been reached.
+	// +optional
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+
+	// Error describes why the most recent activation attempt failed.
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // TupleGenerationJobStatus defines the observed state of a TupleGenerationJob.
 type TupleGenerationJobStatus struct {
 	State                   TupleGenerationJobState `json:"state"`
 	LastStateTransitionTime metav1.Time             `json:"lastStateTransitionTime"`
+
+	// StartTime is the point in time the operator started processing the job, i.e., the job has been admitted.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the point in time the job completed successfully.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Progress is the number of tasks of the job that completed successfully out of the number of VCPs, e.g., 1/2. For
+	// sharded jobs, it is the number of shards that completed successfully out of the number of shards.
+	// +optional
+	Progress string `json:"progress,omitempty"`
+
+	// Tasks summarizes the states of the tasks of the job ordered by player.
+	// +optional
+	Tasks []TaskSummary `json:"tasks,omitempty"`
+
+	// Activation is the result of activating the tuple chunk generated by the job in Castor.
+	// +optional
+	Activation *TupleChunkActivationStatus `json:"activation,omitempty"`
+
+	// Shards summarizes the states of the shards of a sharded job ordered by index.
+	// +optional
+	Shards []ShardStatus `json:"shards,omitempty"`
+
+	// Conditions describe the latest observations of the state of the job.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Reason is a machine-readable CamelCase reason for the job having failed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human-readable description of why the job failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// RetryOf is the name of the failed job this job is a retry of.
+	// +optional
+	RetryOf string `json:"retryOf,omitempty"`
+
+	// Attempt is the number of the retry this job is, i.e., 1 for the first retry of a job and 0 for jobs that are not
+	// a retry.
+	// +optional
+	Attempt int `json:"attempt,omitempty"`
+
+	// RetriedBy is the name of the job retrying this job after it failed.
+	// +optional
+	RetriedBy string `json:"retriedBy,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -74,7 +323,11 @@ type TupleGenerationJobStatus struct {
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Tuple Type",type=string,JSONPath=`.spec.type`
 //+kubebuilder:printcolumn:name="Tuple Count",type=string,JSONPath=`.spec.count`
+//+kubebuilder:printcolumn:name="Generator",type=string,JSONPath=`.spec.generatorRef`
+//+kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`,priority=1
+//+kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
 //+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.state`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.reason`,priority=1
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // TupleGenerationJob is the Schema for the TupleGenerationJob API.
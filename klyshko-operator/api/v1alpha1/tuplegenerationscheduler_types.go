@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022-2023 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -9,6 +9,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // TupleTypePolicy specifies the scheduling policy used for a specific tuple type.
@@ -23,10 +24,189 @@ type TupleTypePolicy struct {
 	//+kubebuilder:validation:ExclusiveMinimum=true
 	Threshold int `json:"threshold"`
 
+	// Target is the fill level up to which tuples are generated once the number of available tuples dropped below the
+	// threshold. Jobs are scheduled for the tuple type until the number of available and in-flight tuples reaches the
+	// target. Defaults to the threshold in case not given or lower than the threshold.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	Target int `json:"target,omitempty"`
+
 	//+kubebuilder:default=1
 	//+kubebuilder:validation:Minimum=0
 	//+kubebuilder:validation:ExclusiveMinimum=true
 	Priority int `json:"priority"`
+
+	// EmergencyThreshold is the number of tuples below which jobs are scheduled for the tuple type even outside the
+	// generation windows or during blackout periods of the scheduler. In addition, the scheduler may exceed its
+	// concurrency up to its burst concurrency to create jobs for the tuple type. The override is disabled in case not
+	// given.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	EmergencyThreshold int `json:"emergencyThreshold,omitempty"`
+
+	// MaxConcurrentJobs is the maximum number of jobs active at the same time for the tuple type. Unlimited, i.e.,
+	// only bounded by the concurrency of the scheduler, in case not given.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentJobs int `json:"maxConcurrentJobs,omitempty"`
+
+	// MinBatchSize is the minimum number of tuples generated by a single job. Enables adaptive batch sizing where the
+	// number of tuples generated by a job is derived from the deficit of the tuple type, i.e., the number of tuples
+	// missing to reach the threshold (or the target while filling), taking in-flight jobs into account. Job sizes are
+	// always multiples of the batch size of the selected generator. In case neither a minimum nor a maximum is given,
+	// each job generates a single batch.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	MinBatchSize int `json:"minBatchSize,omitempty"`
+
+	// MaxBatchSize is the maximum number of tuples generated by a single job. Enables adaptive batch sizing (see
+	// MinBatchSize). A job always generates at least a single batch of the selected generator, even if exceeding the
+	// maximum.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBatchSize int `json:"maxBatchSize,omitempty"`
+
+	// GeneratorSelection configures how the generator is selected in case multiple generators support the tuple type.
+	// +optional
+	GeneratorSelection *GeneratorSelectionSpec `json:"generatorSelection,omitempty"`
+
+	// Budget caps the number of tuples and jobs generated for the tuple type. Unlimited in case not given.
+	// +optional
+	Budget *GenerationBudget `json:"budget,omitempty"`
+}
+
+// GenerationBudget declares quotas for the generation of tuples of a specific type. Quotas are hard limits, i.e.,
+// they are enforced irrespective of the threshold and even if the tuple type dropped below its emergency threshold.
+// Jobs count towards the quotas from the moment they are created, irrespective of whether they complete or fail.
+type GenerationBudget struct {
+
+	// MaxTuples is the maximum number of tuples generated within the rolling window. Jobs are shrunk, if required, to
+	// not exceed the quota. Unlimited in case not given.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	MaxTuples int `json:"maxTuples,omitempty"`
+
+	// Window is the length of the rolling window the MaxTuples quota applies to, e.g., `24h`.
+	//+kubebuilder:default="24h"
+	// +optional
+	Window metav1.Duration `json:"window,omitempty"`
+
+	// MaxJobsPerHour is the maximum number of jobs created within any rolling window of an hour. Unlimited in case not
+	// given.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	MaxJobsPerHour int `json:"maxJobsPerHour,omitempty"`
+}
+
+//+kubebuilder:validation:Enum=Weighted;PreferredWithFallback
+
+// GeneratorSelectionPolicy is the policy used to select a generator among the candidates for a tuple type.
+type GeneratorSelectionPolicy string
+
+const (
+	// WeightedGeneratorSelection splits jobs among the candidate generators proportional to their weights.
+	WeightedGeneratorSelection GeneratorSelectionPolicy = "Weighted"
+
+	// PreferredWithFallbackGeneratorSelection selects the first candidate generator in order of preference whose most
+	// recently finished job for the tuple type did not fail.
+	PreferredWithFallbackGeneratorSelection GeneratorSelectionPolicy = "PreferredWithFallback"
+)
+
+// GeneratorPreference declares a candidate generator along with its weight.
+type GeneratorPreference struct {
+
+	// Name is the name of the generator.
+	//+kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Weight is the relative share of jobs to be generated using the generator when using the Weighted policy. A
+	// generator with weight zero is never selected by the Weighted policy.
+	//+kubebuilder:default=1
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	Weight int `json:"weight"`
+}
+
+// GeneratorSelectionSpec specifies how a generator is selected among the generators supporting a tuple type.
+type GeneratorSelectionSpec struct {
+
+	// Policy is the policy used to select a generator among the candidates.
+	//+kubebuilder:default=Weighted
+	// +optional
+	Policy GeneratorSelectionPolicy `json:"policy,omitempty"`
+
+	// Selector restricts the candidates to the generators matching the selector.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Generators restricts the candidates to the listed generators. The order of the list defines the preference
+	// used by the PreferredWithFallback policy. In case the list is empty, all generators supporting the tuple type are
+	// candidates having a weight of one and are preferred in lexicographical order of their names.
+	// +optional
+	Generators []GeneratorPreference `json:"generators,omitempty"`
+}
+
+// SchedulingStrategySpec selects the strategy used by a scheduler to decide for which tuple type to generate tuples
+// next.
+type SchedulingStrategySpec struct {
+
+	// Name is the name under which the strategy is registered with the operator.
+	//+kubebuilder:default=Lottery
+	//+kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Parameters are handed over to the strategy on instantiation. Supported parameters depend on the strategy.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// TimeWindow is a recurring period of time.
+type TimeWindow struct {
+
+	// Name is an optional name used to refer to the window in the status of the scheduler.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Schedule is a cron expression in standard format, e.g., `0 22 * * MON-FRI`, specifying when the window starts.
+	// Descriptors like `@daily` are supported as well.
+	//+kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is the length of the window, e.g., `8h`.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// FailurePolicySpec specifies how the scheduler reacts to failing jobs. Failures are tracked per tuple type and per
+// combination of tuple type and generator. After a failure, no further jobs are scheduled for the tuple type (or using
+// the generator for the tuple type) until an exponentially growing backoff elapsed. After a number of consecutive
+// failures the circuit breaker opens and no jobs are scheduled until the cool-down elapsed. Afterwards, the circuit
+// breaker is half-open and a single trial job is scheduled. The circuit breaker closes in case the trial job succeeds
+// and opens again otherwise.
+type FailurePolicySpec struct {
+
+	// InitialBackoffSeconds is the backoff after the first failure. The backoff doubles with each consecutive failure.
+	//+kubebuilder:default=30
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	InitialBackoffSeconds int `json:"initialBackoffSeconds,omitempty"`
+
+	// MaxBackoffSeconds is the upper bound for the backoff.
+	//+kubebuilder:default=900
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBackoffSeconds int `json:"maxBackoffSeconds,omitempty"`
+
+	// FailureThreshold is the number of consecutive failures after which the circuit breaker opens.
+	//+kubebuilder:default=5
+	//+kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+
+	// CoolDownSeconds is the time after which an open circuit breaker becomes half-open.
+	//+kubebuilder:default=600
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	CoolDownSeconds int `json:"coolDownSeconds,omitempty"`
 }
 
 // TupleGenerationSchedulerSpec defines the desired state of a TupleGenerationScheduler.
@@ -42,18 +222,366 @@ type TupleGenerationSchedulerSpec struct {
 	//+kubebuilder:validation:ExclusiveMinimum=true
 	TTLSecondsAfterFinished int `json:"ttlSecondsAfterFinished"`
 
+	// BurstConcurrency is the number of jobs that may be active at the same time while the number of tuples available
+	// for any tuple type is below the emergency threshold of its policy. Jobs beyond the regular concurrency are only
+	// created for tuple types below their emergency threshold. Burst mode is disabled in case not given or not greater
+	// than the regular concurrency.
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	BurstConcurrency int `json:"burstConcurrency,omitempty"`
+
 	//+kubebuilder:validation:Required
 	//+kubebuilder:validation:MinItems=1
 	TupleTypePolicies []TupleTypePolicy `json:"policies"`
+
+	// Suspend tells the scheduler to not create any new jobs. Active jobs are not affected and run to completion.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Windows restrict the generation of tuples to the given periods of time. Tuples are generated at any time in case
+	// no window is given.
+	// +optional
+	Windows []TimeWindow `json:"windows,omitempty"`
+
+	// Blackouts are periods of time during which no tuples are generated, even within a window.
+	// +optional
+	Blackouts []TimeWindow `json:"blackouts,omitempty"`
+
+	// TimeZone is the name of the time zone in the IANA Time Zone database, e.g., `Europe/Berlin`, used to evaluate
+	// the schedules of windows and blackouts. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// FailurePolicy specifies how the scheduler reacts to failing jobs.
+	//+kubebuilder:default={}
+	// +optional
+	FailurePolicy FailurePolicySpec `json:"failurePolicy,omitempty"`
+
+	// RetryPolicy specifies whether and how failed jobs created by the scheduler are retried. Jobs are not retried in
+	// case not given.
+	// +optional
+	RetryPolicy *JobRetryPolicy `json:"retryPolicy,omitempty"`
+
+	// JobDeadlineSeconds is the active deadline of the jobs created by the scheduler. Jobs have no deadline in case not
+	// given.
+	//+kubebuilder:validation:Minimum=1
+	// +optional
+	JobDeadlineSeconds *int64 `json:"jobDeadlineSeconds,omitempty"`
+
+	// TaskTimeouts bounds the time the tasks of the jobs created by the scheduler may stay in the individual states.
+	// +optional
+	TaskTimeouts *TaskTimeouts `json:"taskTimeouts,omitempty"`
+
+	// JobPriority is the priority of the jobs created by the scheduler used to decide on the order in which queued jobs
+	// are admitted.
+	// +optional
+	JobPriority int `json:"jobPriority,omitempty"`
+
+	// JobSharding splits the jobs created by the scheduler into shards generating tuples in parallel. Jobs are not split
+	// in case not given.
+	// +optional
+	JobSharding *JobShardingSpec `json:"jobSharding,omitempty"`
+
+	// Strategy selects the scheduling strategy used by the scheduler. Defaults to the lottery strategy.
+	//+kubebuilder:default={name: Lottery}
+	// +optional
+	Strategy SchedulingStrategySpec `json:"strategy,omitempty"`
+}
+
+const (
+	// SchedulerStrategyAccepted is the type of the condition signalling whether the scheduling strategy referenced by a
+	// scheduler is known to the operator and has been instantiated successfully using the given parameters.
+	SchedulerStrategyAccepted = "StrategyAccepted"
+
+	// SchedulerCastorReachable is the type of the condition signalling whether telemetry data could be fetched from
+	// Castor the last time it was attempted.
+	SchedulerCastorReachable = "CastorReachable"
+
+	// SchedulerAtConcurrencyLimit is the type of the condition signalling whether the number of active jobs has reached
+	// the concurrency limit of the scheduler.
+	SchedulerAtConcurrencyLimit = "AtConcurrencyLimit"
+
+	// SchedulerWindowOpen is the type of the condition signalling whether tuples can be generated at the moment
+	// according to the windows and blackouts of the scheduler.
+	SchedulerWindowOpen = "WindowOpen"
+
+	// SchedulerDrained is the type of the condition signalling whether all jobs of a suspended or draining scheduler
+	// finished. The condition is only present while the scheduler is suspended or the operator is in drain mode.
+	SchedulerDrained = "Drained"
+
+	// SchedulerPoliciesServiceable is the type of the condition signalling whether tuples can be generated for all tuple
+	// types a policy is declared for.
+	SchedulerPoliciesServiceable = "PoliciesServiceable"
+
+	// SchedulerBursting is the type of the condition signalling whether the scheduler is in burst mode, i.e., may
+	// exceed its regular concurrency as tuple types are below their emergency threshold. The condition is only present
+	// in case burst mode is enabled.
+	SchedulerBursting = "Bursting"
+
+	// SchedulerCoordinating is the type of the condition signalling whether the scheduler runs on the coordinating VCP.
+	// Schedulers on other VCPs are passive, i.e., don't create jobs, as the rosters of jobs are written by the
+	// coordinator only.
+	SchedulerCoordinating = "Coordinating"
+
+	// SchedulerTelemetryConsistent is the type of the condition signalling whether the telemetry data published by all
+	// VCPs is available and reports the same number of tuples available for all tuple types. The condition is only
+	// present on the coordinating VCP.
+	SchedulerTelemetryConsistent = "TelemetryConsistent"
+)
+
+// TupleTypeTelemetry is the telemetry data reported by Castor for a single tuple type.
+type TupleTypeTelemetry struct {
+	Type            string `json:"type"`
+	Available       int    `json:"available"`
+	ConsumptionRate int    `json:"consumptionRate"`
+
+	// Divergence is the difference between the highest and the lowest number of tuples available across the VCPs.
+	// +optional
+	Divergence int `json:"divergence,omitempty"`
+}
+
+// TelemetrySnapshot is the telemetry data reported by Castor at a specific point in time. On the coordinating VCP,
+// the snapshot combines the telemetry data published by all VCPs, i.e., the number of available tuples is the minimum
+// across all VCPs.
+type TelemetrySnapshot struct {
+
+	// Time is the point in time the telemetry data has been fetched from Castor. Retained as long as the data does not
+	// change.
+	Time metav1.Time `json:"time"`
+
+	// +optional
+	TupleTypes []TupleTypeTelemetry `json:"tupleTypes,omitempty"`
+
+	// MissingPlayers are the identifiers of the players whose telemetry data is not available or outdated and
+	// therefore has not been taken into account.
+	// +optional
+	MissingPlayers []uint `json:"missingPlayers,omitempty"`
+}
+
+// UnserviceablePolicy describes why tuples can't be generated for the tuple type of a policy.
+type UnserviceablePolicy struct {
+	Type string `json:"type"`
+
+	// Reason is a machine-readable CamelCase reason for the policy being unserviceable.
+	Reason string `json:"reason"`
+
+	// Message is a human-readable description of why the policy is unserviceable.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	// NoGeneratorAvailable is the reason for a policy being unserviceable because there is no generator for its tuple
+	// type.
+	NoGeneratorAvailable = "NoGeneratorAvailable"
+
+	// NoGeneratorSelectable is the reason for a policy being unserviceable because none of the generators for its tuple
+	// type is a candidate according to the generator selection of the policy.
+	NoGeneratorSelectable = "NoGeneratorSelectable"
+
+	// InvalidGeneratorSelection is the reason for a policy being unserviceable because its generator selection is
+	// malformed.
+	InvalidGeneratorSelection = "InvalidGeneratorSelection"
+)
+
+// SchedulingDecision is the outcome of running the scheduling strategy of a scheduler.
+type SchedulingDecision struct {
+
+	// Time is the point in time the decision has been taken. Retained as long as the same decision is taken again.
+	Time metav1.Time `json:"time"`
+
+	// TupleType is the tuple type selected by the strategy. Empty in case the strategy decided not to generate tuples.
+	// +optional
+	TupleType string `json:"tupleType,omitempty"`
+
+	// Generator is the name of the generator used to generate the tuples.
+	// +optional
+	Generator string `json:"generator,omitempty"`
+
+	// Count is the number of tuples to be generated by the job created as a result of the decision.
+	// +optional
+	Count int `json:"count,omitempty"`
+
+	// Job is the name of the job created as a result of the decision.
+	// +optional
+	Job string `json:"job,omitempty"`
+}
+
+// TupleTypeStatus is the observed state of scheduling jobs for a single tuple type.
+type TupleTypeStatus struct {
+	Type string `json:"type"`
+
+	// ActiveJobs is the number of jobs for the tuple type that are neither completed nor failed.
+	ActiveJobs int `json:"activeJobs"`
+
+	// Filling signals that the number of tuples dropped below the threshold and jobs are scheduled until the target
+	// is reached.
+	// +optional
+	Filling bool `json:"filling,omitempty"`
+
+	// Emergency signals that the number of tuples dropped below the emergency threshold, i.e., jobs are scheduled for
+	// the tuple type irrespective of the windows and blackouts of the scheduler.
+	// +optional
+	Emergency bool `json:"emergency,omitempty"`
+
+	// AtConcurrencyLimit signals that the number of active jobs for the tuple type has reached the maximum number of
+	// concurrent jobs declared in the policy.
+	// +optional
+	AtConcurrencyLimit bool `json:"atConcurrencyLimit,omitempty"`
+
+	// BudgetExhausted signals that no jobs are scheduled for the tuple type as one of the quotas of its budget is used
+	// up.
+	// +optional
+	BudgetExhausted bool `json:"budgetExhausted,omitempty"`
+}
+
+// BudgetRecord records a finished job that counts towards the budget of its tuple type.
+type BudgetRecord struct {
+
+	// Job is the name of the job.
+	Job string `json:"job"`
+
+	// Type is the tuple type generated by the job.
+	Type string `json:"type"`
+
+	// Count is the number of tuples generated by the job.
+	Count int `json:"count"`
+
+	// Time is the time the job has been created.
+	Time metav1.Time `json:"time"`
+}
+
+// CircuitBreakerState is the state of a circuit breaker.
+type CircuitBreakerState string
+
+const (
+	// CircuitBreakerClosed is the state of a circuit breaker that allows jobs to be scheduled subject to backoff.
+	CircuitBreakerClosed CircuitBreakerState = "Closed"
+
+	// CircuitBreakerOpen is the state of a circuit breaker that prevents jobs from being scheduled.
+	CircuitBreakerOpen CircuitBreakerState = "Open"
+
+	// CircuitBreakerHalfOpen is the state of a circuit breaker that allows a single trial job to be scheduled.
+	CircuitBreakerHalfOpen CircuitBreakerState = "HalfOpen"
+)
+
+// FailureStatus is the observed failure state for a tuple type or for the combination of a tuple type and a generator.
+type FailureStatus struct {
+	Type string `json:"type"`
+
+	// Generator is the name of the generator. Empty in case the status is tracked for the tuple type as a whole.
+	// +optional
+	Generator string `json:"generator,omitempty"`
+
+	// ConsecutiveFailures is the number of jobs that failed since the last job that completed successfully.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+
+	// State is the state of the circuit breaker.
+	State CircuitBreakerState `json:"state"`
+
+	// LastFailureTime is the point in time the most recent failure has been observed.
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// RetryTime is the point in time after which jobs are scheduled again, i.e., the end of the backoff or the end of
+	// the cool-down in case the circuit breaker is open.
+	// +optional
+	RetryTime *metav1.Time `json:"retryTime,omitempty"`
+}
+
+// ObservedJob references a finished job that has been taken into account for tracking failures.
+type ObservedJob struct {
+	Name string `json:"name"`
+
+	// UID is the unique identifier of the job.
+	UID types.UID `json:"uid"`
+
+	// Time is the point in time the job finished.
+	Time metav1.Time `json:"time"`
+}
+
+// BurstEpisode is a period of time the scheduler has been in burst mode.
+type BurstEpisode struct {
+
+	// StartTime is the point in time the scheduler entered burst mode.
+	StartTime metav1.Time `json:"startTime"`
+
+	// EndTime is the point in time the scheduler left burst mode. Not set for an ongoing episode.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// TupleTypes are the tuple types that dropped below their emergency threshold during the episode.
+	TupleTypes []string `json:"tupleTypes"`
+
+	// Jobs is the number of jobs created beyond the regular concurrency during the episode.
+	Jobs int `json:"jobs"`
 }
 
 // TupleGenerationSchedulerStatus defines the observed state of a TupleGenerationScheduler.
 type TupleGenerationSchedulerStatus struct {
+
+	// Conditions describe the latest observations of the state of the scheduler.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ActiveJobs is the number of jobs that are neither completed nor failed.
+	// +optional
+	ActiveJobs int `json:"activeJobs"`
+
+	// LastTelemetry is the telemetry data most recently fetched from Castor.
+	// +optional
+	LastTelemetry *TelemetrySnapshot `json:"lastTelemetry,omitempty"`
+
+	// ServiceablePolicies are the tuple types of the policies tuples can be generated for.
+	// +optional
+	ServiceablePolicies []string `json:"serviceablePolicies,omitempty"`
+
+	// UnserviceablePolicies are the policies tuples can't be generated for along with the respective reason.
+	// +optional
+	UnserviceablePolicies []UnserviceablePolicy `json:"unserviceablePolicies,omitempty"`
+
+	// TupleTypes is the observed state of scheduling jobs for the tuple types of the serviceable policies.
+	// +optional
+	TupleTypes []TupleTypeStatus `json:"tupleTypes,omitempty"`
+
+	// Failures is the failure state for tuple types and combinations of tuple types and generators that recently
+	// experienced failing jobs.
+	// +optional
+	Failures []FailureStatus `json:"failures,omitempty"`
+
+	// ObservedJobs are the finished jobs that have been taken into account for tracking failures and have not been
+	// deleted yet.
+	// +optional
+	ObservedJobs []ObservedJob `json:"observedJobs,omitempty"`
+
+	// BudgetHistory records the finished jobs that still count towards the budgets of the tuple type policies. Required
+	// as finished jobs are deleted after their TTL expired.
+	// +optional
+	BudgetHistory []BudgetRecord `json:"budgetHistory,omitempty"`
+
+	// BurstEpisodes are the most recent burst episodes of the scheduler, the latest one last.
+	// +optional
+	BurstEpisodes []BurstEpisode `json:"burstEpisodes,omitempty"`
+
+	// LastDecision is the outcome of the most recent invocation of the scheduling strategy.
+	// +optional
+	LastDecision *SchedulingDecision `json:"lastDecision,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:resource:shortName=tgs;tgscheduler
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`
+//+kubebuilder:printcolumn:name="Strategy",type=string,JSONPath=`.spec.strategy.name`
+//+kubebuilder:printcolumn:name="Active Jobs",type=integer,JSONPath=`.status.activeJobs`
+//+kubebuilder:printcolumn:name="Last Decision",type=string,JSONPath=`.status.lastDecision.tupleType`
+//+kubebuilder:printcolumn:name="Decided",type="date",JSONPath=`.status.lastDecision.time`
+//+kubebuilder:printcolumn:name="Window",type=string,JSONPath=`.status.conditions[?(@.type=="WindowOpen")].reason`
+//+kubebuilder:printcolumn:name="Coordinating",type=string,JSONPath=`.status.conditions[?(@.type=="Coordinating")].status`,priority=1
+//+kubebuilder:printcolumn:name="Burst",type=string,JSONPath=`.status.conditions[?(@.type=="Bursting")].status`,priority=1
+//+kubebuilder:printcolumn:name="Castor",type=string,JSONPath=`.status.conditions[?(@.type=="CastorReachable")].status`,priority=1
+//+kubebuilder:printcolumn:name="Serviceable",type=string,JSONPath=`.status.conditions[?(@.type=="PoliciesServiceable")].status`,priority=1
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // TupleGenerationScheduler is the Schema for the TupleGenerationScheduler API.
 type TupleGenerationScheduler struct {
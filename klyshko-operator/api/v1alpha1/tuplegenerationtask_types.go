@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022-2023 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -34,12 +34,15 @@ const (
 
 	// TaskFailed means that an error occurred while performing the task.
 	TaskFailed TupleGenerationTaskState = "Failed"
+
+	// TaskCancelled means that the task has been terminated as its job has been cancelled.
+	TaskCancelled TupleGenerationTaskState = "Cancelled"
 )
 
 // IsValid returns true if state s is among the defined ones and false otherwise.
 func (s TupleGenerationTaskState) IsValid() bool {
 	switch s {
-	case TaskPreparing, TaskLaunching, TaskGenerating, TaskProvisioning, TaskCompleted, TaskFailed:
+	case TaskPreparing, TaskLaunching, TaskGenerating, TaskProvisioning, TaskCompleted, TaskFailed, TaskCancelled:
 		return true
 	default:
 		return false
@@ -51,10 +54,39 @@ type TupleGenerationTaskSpec struct {
 	PlayerID uint `json:"playerId"`
 }
 
+// IsDone returns true if s is among the set of TupleGenerationTaskState that describe a task that is done, i.e., is
+// either TaskCompleted, TaskFailed, or TaskCancelled, and false otherwise.
+func (s TupleGenerationTaskState) IsDone() bool {
+	return s == TaskCompleted || s == TaskFailed || s == TaskCancelled
+}
+
+const (
+	// TaskTimedOut is the reason for a task failing because it exceeded the timeout of its state.
+	TaskTimedOut = "TimedOut"
+
+	// TaskJobFailed is the reason for a task failing because its job failed, e.g., as its deadline has been exceeded.
+	TaskJobFailed = "JobFailed"
+
+	// TaskPodFailed is the reason for a task failing because its generator or provisioner pod failed.
+	TaskPodFailed = "PodFailed"
+)
+
 // TupleGenerationTaskStatus defines the observed state of a TupleGenerationTask.
 type TupleGenerationTaskStatus struct {
 	State    TupleGenerationTaskState `json:"state"`
 	Endpoint string                   `json:"endpoint,omitempty"`
+
+	// LastStateTransitionTime is the point in time the task entered its current state.
+	// +optional
+	LastStateTransitionTime *metav1.Time `json:"lastStateTransitionTime,omitempty"`
+
+	// Reason is a machine-readable CamelCase reason for the task having failed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human-readable description of why the task failed.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // Unmarshal parses a JSON serialized TupleGenerationTaskStatus.
@@ -21,16 +21,343 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BudgetRecord) DeepCopyInto(out *BudgetRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetRecord.
+func (in *BudgetRecord) DeepCopy() *BudgetRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(BudgetRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BurstEpisode) DeepCopyInto(out *BurstEpisode) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.TupleTypes != nil {
+		in, out := &in.TupleTypes, &out.TupleTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BurstEpisode.
+func (in *BurstEpisode) DeepCopy() *BurstEpisode {
+	if in == nil {
+		return nil
+	}
+	out := new(BurstEpisode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailurePolicySpec) DeepCopyInto(out *FailurePolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailurePolicySpec.
+func (in *FailurePolicySpec) DeepCopy() *FailurePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FailurePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureStatus) DeepCopyInto(out *FailureStatus) {
+	*out = *in
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RetryTime != nil {
+		in, out := &in.RetryTime, &out.RetryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureStatus.
+func (in *FailureStatus) DeepCopy() *FailureStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenerationBudget) DeepCopyInto(out *GenerationBudget) {
+	*out = *in
+	out.Window = in.Window
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenerationBudget.
+func (in *GenerationBudget) DeepCopy() *GenerationBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(GenerationBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorPreference) DeepCopyInto(out *GeneratorPreference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorPreference.
+func (in *GeneratorPreference) DeepCopy() *GeneratorPreference {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorPreference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorSelectionSpec) DeepCopyInto(out *GeneratorSelectionSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]GeneratorPreference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorSelectionSpec.
+func (in *GeneratorSelectionSpec) DeepCopy() *GeneratorSelectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorSelectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobRetryPolicy) DeepCopyInto(out *JobRetryPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobRetryPolicy.
+func (in *JobRetryPolicy) DeepCopy() *JobRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(JobRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobShardingSpec) DeepCopyInto(out *JobShardingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobShardingSpec.
+func (in *JobShardingSpec) DeepCopy() *JobShardingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JobShardingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedJob) DeepCopyInto(out *ObservedJob) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedJob.
+func (in *ObservedJob) DeepCopy() *ObservedJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingDecision) DeepCopyInto(out *SchedulingDecision) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingDecision.
+func (in *SchedulingDecision) DeepCopy() *SchedulingDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingStrategySpec) DeepCopyInto(out *SchedulingStrategySpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingStrategySpec.
+func (in *SchedulingStrategySpec) DeepCopy() *SchedulingStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardStatus) DeepCopyInto(out *ShardStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardStatus.
+func (in *ShardStatus) DeepCopy() *ShardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskSummary) DeepCopyInto(out *TaskSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSummary.
+func (in *TaskSummary) DeepCopy() *TaskSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskTimeouts) DeepCopyInto(out *TaskTimeouts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskTimeouts.
+func (in *TaskTimeouts) DeepCopy() *TaskTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TelemetrySnapshot) DeepCopyInto(out *TelemetrySnapshot) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.TupleTypes != nil {
+		in, out := &in.TupleTypes, &out.TupleTypes
+		*out = make([]TupleTypeTelemetry, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingPlayers != nil {
+		in, out := &in.MissingPlayers, &out.MissingPlayers
+		*out = make([]uint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelemetrySnapshot.
+func (in *TelemetrySnapshot) DeepCopy() *TelemetrySnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(TelemetrySnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeWindow) DeepCopyInto(out *TimeWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeWindow.
+func (in *TimeWindow) DeepCopy() *TimeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupleChunkActivationStatus) DeepCopyInto(out *TupleChunkActivationStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.NextAttemptTime != nil {
+		in, out := &in.NextAttemptTime, &out.NextAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleChunkActivationStatus.
+func (in *TupleChunkActivationStatus) DeepCopy() *TupleChunkActivationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TupleChunkActivationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupleGenerationJob) DeepCopyInto(out *TupleGenerationJob) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -87,6 +414,26 @@ func (in *TupleGenerationJobList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupleGenerationJobSpec) DeepCopyInto(out *TupleGenerationJobSpec) {
 	*out = *in
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(JobRetryPolicy)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TaskTimeouts != nil {
+		in, out := &in.TaskTimeouts, &out.TaskTimeouts
+		*out = new(TaskTimeouts)
+		**out = **in
+	}
+	if in.Sharding != nil {
+		in, out := &in.Sharding, &out.Sharding
+		*out = new(JobShardingSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleGenerationJobSpec.
@@ -103,6 +450,36 @@ func (in *TupleGenerationJobSpec) DeepCopy() *TupleGenerationJobSpec {
 func (in *TupleGenerationJobStatus) DeepCopyInto(out *TupleGenerationJobStatus) {
 	*out = *in
 	in.LastStateTransitionTime.DeepCopyInto(&out.LastStateTransitionTime)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]TaskSummary, len(*in))
+		copy(*out, *in)
+	}
+	if in.Activation != nil {
+		in, out := &in.Activation, &out.Activation
+		*out = new(TupleChunkActivationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]ShardStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleGenerationJobStatus.
@@ -121,7 +498,7 @@ func (in *TupleGenerationScheduler) DeepCopyInto(out *TupleGenerationScheduler)
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleGenerationScheduler.
@@ -180,8 +557,42 @@ func (in *TupleGenerationSchedulerSpec) DeepCopyInto(out *TupleGenerationSchedul
 	if in.TupleTypePolicies != nil {
 		in, out := &in.TupleTypePolicies, &out.TupleTypePolicies
 		*out = make([]TupleTypePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]TimeWindow, len(*in))
 		copy(*out, *in)
 	}
+	if in.Blackouts != nil {
+		in, out := &in.Blackouts, &out.Blackouts
+		*out = make([]TimeWindow, len(*in))
+		copy(*out, *in)
+	}
+	out.FailurePolicy = in.FailurePolicy
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(JobRetryPolicy)
+		**out = **in
+	}
+	if in.JobDeadlineSeconds != nil {
+		in, out := &in.JobDeadlineSeconds, &out.JobDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TaskTimeouts != nil {
+		in, out := &in.TaskTimeouts, &out.TaskTimeouts
+		*out = new(TaskTimeouts)
+		**out = **in
+	}
+	if in.JobSharding != nil {
+		in, out := &in.JobSharding, &out.JobSharding
+		*out = new(JobShardingSpec)
+		**out = **in
+	}
+	in.Strategy.DeepCopyInto(&out.Strategy)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleGenerationSchedulerSpec.
@@ -197,6 +608,66 @@ func (in *TupleGenerationSchedulerSpec) DeepCopy() *TupleGenerationSchedulerSpec
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupleGenerationSchedulerStatus) DeepCopyInto(out *TupleGenerationSchedulerStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastTelemetry != nil {
+		in, out := &in.LastTelemetry, &out.LastTelemetry
+		*out = new(TelemetrySnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceablePolicies != nil {
+		in, out := &in.ServiceablePolicies, &out.ServiceablePolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnserviceablePolicies != nil {
+		in, out := &in.UnserviceablePolicies, &out.UnserviceablePolicies
+		*out = make([]UnserviceablePolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.TupleTypes != nil {
+		in, out := &in.TupleTypes, &out.TupleTypes
+		*out = make([]TupleTypeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failures != nil {
+		in, out := &in.Failures, &out.Failures
+		*out = make([]FailureStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ObservedJobs != nil {
+		in, out := &in.ObservedJobs, &out.ObservedJobs
+		*out = make([]ObservedJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BudgetHistory != nil {
+		in, out := &in.BudgetHistory, &out.BudgetHistory
+		*out = make([]BudgetRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BurstEpisodes != nil {
+		in, out := &in.BurstEpisodes, &out.BurstEpisodes
+		*out = make([]BurstEpisode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDecision != nil {
+		in, out := &in.LastDecision, &out.LastDecision
+		*out = new(SchedulingDecision)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleGenerationSchedulerStatus.
@@ -215,7 +686,7 @@ func (in *TupleGenerationTask) DeepCopyInto(out *TupleGenerationTask) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleGenerationTask.
@@ -286,6 +757,10 @@ func (in *TupleGenerationTaskSpec) DeepCopy() *TupleGenerationTaskSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupleGenerationTaskStatus) DeepCopyInto(out *TupleGenerationTaskStatus) {
 	*out = *in
+	if in.LastStateTransitionTime != nil {
+		in, out := &in.LastStateTransitionTime, &out.LastStateTransitionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleGenerationTaskStatus.
@@ -378,9 +853,16 @@ func (in *TupleGeneratorPodSpec) DeepCopyInto(out *TupleGeneratorPodSpec) {
 	*out = *in
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Container.DeepCopyInto(&out.Container)
 }
 
@@ -449,6 +931,16 @@ func (in *TupleGeneratorStatus) DeepCopy() *TupleGeneratorStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupleTypePolicy) DeepCopyInto(out *TupleTypePolicy) {
 	*out = *in
+	if in.GeneratorSelection != nil {
+		in, out := &in.GeneratorSelection, &out.GeneratorSelection
+		*out = new(GeneratorSelectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(GenerationBudget)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleTypePolicy.
@@ -475,3 +967,48 @@ func (in *TupleTypeSpec) DeepCopy() *TupleTypeSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupleTypeStatus) DeepCopyInto(out *TupleTypeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleTypeStatus.
+func (in *TupleTypeStatus) DeepCopy() *TupleTypeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TupleTypeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupleTypeTelemetry) DeepCopyInto(out *TupleTypeTelemetry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupleTypeTelemetry.
+func (in *TupleTypeTelemetry) DeepCopy() *TupleTypeTelemetry {
+	if in == nil {
+		return nil
+	}
+	out := new(TupleTypeTelemetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnserviceablePolicy) DeepCopyInto(out *UnserviceablePolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnserviceablePolicy.
+func (in *UnserviceablePolicy) DeepCopy() *UnserviceablePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UnserviceablePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -10,6 +10,7 @@ package castor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/carbynestack/klyshko/logging"
 	"github.com/google/uuid"
@@ -19,6 +20,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// tupleChunkActivated is the activation status Castor reports for tuple chunks that have been activated, i.e., whose
+// tuples have been unlocked for consumption.
+const tupleChunkActivated = "UNLOCKED"
+
+// ErrTupleChunkAlreadyActivated is returned when activating a tuple chunk that has been activated before.
+var ErrTupleChunkAlreadyActivated = errors.New("tuple chunk activated already")
+
+// ErrTupleChunkNotFound is returned when querying the activation status of a tuple chunk unknown to Castor.
+var ErrTupleChunkNotFound = errors.New("tuple chunk not found")
+
+// TupleChunkStatus describes the activation status of a tuple chunk stored by Castor.
+type TupleChunkStatus struct {
+	ChunkID uuid.UUID `json:"chunkId"`
+	Status  string    `json:"status"`
+}
+
 // Client is a client for the Castor tuple store.
 type Client struct {
 	URL    string
@@ -33,7 +50,43 @@ func NewClient(url string) *Client {
 	}
 }
 
-// ActivateTupleChunk activates the tuple chunk with the given chunk identifier stored by the Castor service.
+// IsTupleChunkActivated checks whether the tuple chunk with the given chunk identifier stored by the Castor service has
+// been activated. Returns ErrTupleChunkNotFound in case Castor doesn't know the tuple chunk.
+func (c Client) IsTupleChunkActivated(ctx context.Context, chunkID uuid.UUID) (bool, error) {
+	logger := log.FromContext(ctx).WithValues("TupleChunkId", chunkID)
+	url := fmt.Sprintf("%s/intra-vcp/tuple-chunks/%s/status", c.URL, chunkID)
+	logger.V(logging.DEBUG).Info("Fetching tuple chunk status with castor URL", "URL", url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Add("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			logger.Error(err, "Failed to close response from castor")
+		}
+	}()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, ErrTupleChunkNotFound
+	default:
+		return false, fmt.Errorf("received response with status code %d", resp.StatusCode)
+	}
+	var status TupleChunkStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return false, fmt.Errorf("failed to parse tuple chunk status: %w", err)
+	}
+	return status.Status == tupleChunkActivated, nil
+}
+
+// ActivateTupleChunk activates the tuple chunk with the given chunk identifier stored by the Castor service. Returns
+// ErrTupleChunkAlreadyActivated in case Castor reports a conflict as the tuple chunk has been activated before.
 func (c Client) ActivateTupleChunk(ctx context.Context, chunkID uuid.UUID) error {
 	logger := log.FromContext(ctx).WithValues("TupleChunkId", chunkID)
 	url := fmt.Sprintf("%s/intra-vcp/tuple-chunks/activate/%s", c.URL, chunkID)
@@ -46,9 +99,6 @@ func (c Client) ActivateTupleChunk(ctx context.Context, chunkID uuid.UUID) error
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("received response with status code %d", resp.StatusCode)
-	}
 	defer func() {
 		_, err := io.Copy(ioutil.Discard, resp.Body)
 		if err != nil {
@@ -60,7 +110,14 @@ func (c Client) ActivateTupleChunk(ctx context.Context, chunkID uuid.UUID) error
 		}
 	}()
 	logger.V(logging.DEBUG).Info("Response from castor", "Status", resp.Status)
-	return nil
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		return ErrTupleChunkAlreadyActivated
+	default:
+		return fmt.Errorf("received response with status code %d", resp.StatusCode)
+	}
 }
 
 // TupleMetrics stores how many tuples are available for a given tuple type and how fast they are consumed.
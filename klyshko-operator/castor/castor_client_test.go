@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -123,6 +123,23 @@ var _ = Describe("Activating a tuple chunk", func() {
 		})
 	})
 
+	When("when Castor service responds with conflict status code", func() {
+		BeforeEach(func() {
+			httpmock.Activate()
+			httpmock.RegisterResponder(
+				"PUT",
+				fmt.Sprintf("=~^%s/intra-vcp/tuple-chunks/activate/.*", validCastorURL),
+				httpmock.NewStringResponder(409, ""),
+			)
+		})
+		It("reports the tuple chunk to be activated already", func() {
+			chunkID := uuid.New()
+			castorClient := NewClient(validCastorURL)
+			err := castorClient.ActivateTupleChunk(ctx, chunkID)
+			Expect(err).To(MatchError(ErrTupleChunkAlreadyActivated))
+		})
+	})
+
 	When("when Castor service is not available", func() {
 		It("fails", func() {
 			chunkID := uuid.New()
@@ -134,6 +151,68 @@ var _ = Describe("Activating a tuple chunk", func() {
 
 })
 
+var _ = Describe("Checking whether a tuple chunk is activated", func() {
+
+	ctx := context.TODO()
+	chunkID := uuid.New()
+
+	respondWith := func(responder httpmock.Responder) {
+		httpmock.Activate()
+		httpmock.RegisterResponder(
+			"GET",
+			fmt.Sprintf("%s/intra-vcp/tuple-chunks/%s/status", validCastorURL, chunkID),
+			responder,
+		)
+	}
+
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	When("when Castor service reports the tuple chunk to be unlocked", func() {
+		BeforeEach(func() {
+			respondWith(httpmock.NewStringResponder(200, fmt.Sprintf(`{"chunkId":"%s","status":"UNLOCKED"}`, chunkID)))
+		})
+		It("reports the tuple chunk to be activated", func() {
+			activated, err := NewClient(validCastorURL).IsTupleChunkActivated(ctx, chunkID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(activated).To(BeTrue())
+		})
+	})
+
+	When("when Castor service reports the tuple chunk to be locked", func() {
+		BeforeEach(func() {
+			respondWith(httpmock.NewStringResponder(200, fmt.Sprintf(`{"chunkId":"%s","status":"LOCKED"}`, chunkID)))
+		})
+		It("reports the tuple chunk not to be activated", func() {
+			activated, err := NewClient(validCastorURL).IsTupleChunkActivated(ctx, chunkID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(activated).To(BeFalse())
+		})
+	})
+
+	When("when Castor service doesn't know the tuple chunk", func() {
+		BeforeEach(func() {
+			respondWith(httpmock.NewStringResponder(404, ""))
+		})
+		It("fails", func() {
+			_, err := NewClient(validCastorURL).IsTupleChunkActivated(ctx, chunkID)
+			Expect(err).To(MatchError(ErrTupleChunkNotFound))
+		})
+	})
+
+	When("when Castor service responds with unexpected data", func() {
+		BeforeEach(func() {
+			respondWith(httpmock.NewStringResponder(200, "unexpected"))
+		})
+		It("fails", func() {
+			_, err := NewClient(validCastorURL).IsTupleChunkActivated(ctx, chunkID)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+})
+
 var _ = When("Creating a deep copy of a telemetry struct", func() {
 	It("succeeds", func() {
 		original := &Telemetry{TupleMetrics: []TupleMetrics{
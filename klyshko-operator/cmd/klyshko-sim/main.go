@@ -0,0 +1,83 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// The klyshko-sim command simulates a tuple generation scheduler offline. It feeds recorded or synthetic telemetry
+// into the configured scheduling strategy and reports fill levels, job counts, starvation and exhaustion events.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	// Embed the time zone database used to evaluate the generation windows of schedulers
+	_ "time/tzdata"
+
+	"github.com/carbynestack/klyshko/controllers"
+	"github.com/carbynestack/klyshko/simulation"
+)
+
+var (
+	configFile    = flag.String("config", "", "The simulation config file (YAML or JSON).")
+	telemetryFile = flag.String("telemetry", "", "An optional file (YAML or JSON) with recorded Castor telemetry samples.")
+	csvFile       = flag.String("csv", "", "An optional file the sampled fill levels are written to in CSV format.")
+	verbose       = flag.Bool("verbose", false, "Print all events that occurred during the simulation.")
+)
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "klyshko-sim: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if *configFile == "" {
+		return fmt.Errorf("no simulation config given")
+	}
+	config, err := simulation.LoadConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	var telemetry []simulation.TelemetrySample
+	if *telemetryFile != "" {
+		telemetry, err = simulation.LoadTelemetry(*telemetryFile)
+		if err != nil {
+			return err
+		}
+	}
+	strategy, err := controllers.NewSchedulingStrategyRegistry().New(config.Scheduler.Strategy)
+	if err != nil {
+		return fmt.Errorf("failed to create scheduling strategy: %w", err)
+	}
+
+	result, err := simulation.NewSimulator(*config, strategy, telemetry).Run(context.Background())
+	if err != nil {
+		return fmt.Errorf("simulation failed: %w", err)
+	}
+	if *verbose {
+		if err := simulation.WriteEvents(os.Stdout, result); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	if err := simulation.WriteSummary(os.Stdout, result); err != nil {
+		return err
+	}
+	if *csvFile != "" {
+		f, err := os.Create(*csvFile)
+		if err != nil {
+			return fmt.Errorf("failed to create CSV file: %w", err)
+		}
+		defer f.Close()
+		if err := simulation.WriteCSV(f, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"net/http"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetupWebhooksWithManager registers the validating and defaulting admission webhooks for all Klyshko resources with
+// the webhook server of the given manager. The given registry is used to validate the scheduling strategies of
+// schedulers. Jobs created by the user with the given name, i.e., the operator, are not checked for their generator.
+func SetupWebhooksWithManager(mgr ctrl.Manager, registry *SchedulingStrategyRegistry, operatorUsername string) {
+	server := mgr.GetWebhookServer()
+	c := mgr.GetClient()
+	server.Register("/mutate-klyshko-carbnyestack-io-v1alpha1-tuplegenerationjob",
+		&webhook.Admission{Handler: &TupleGenerationJobDefaulter{}})
+	server.Register("/validate-klyshko-carbnyestack-io-v1alpha1-tuplegenerationjob",
+		&webhook.Admission{Handler: &TupleGenerationJobValidator{Client: c, OperatorUsername: operatorUsername}})
+	server.Register("/validate-klyshko-carbnyestack-io-v1alpha1-tuplegenerationscheduler",
+		&webhook.Admission{Handler: &TupleGenerationSchedulerValidator{Registry: registry}})
+	server.Register("/validate-klyshko-carbnyestack-io-v1alpha1-tuplegenerator",
+		&webhook.Admission{Handler: &TupleGeneratorValidator{}})
+	server.Register("/validate-klyshko-carbnyestack-io-v1alpha1-tuplegenerationtask",
+		&webhook.Admission{Handler: &TupleGenerationTaskValidator{Client: c}})
+}
+
+// decoderInjector provides the admission request decoder to the webhooks embedding it.
+type decoderInjector struct {
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder used to decode the objects contained in admission requests.
+func (d *decoderInjector) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// validationResponse denies the admission request for the resource of the given kind and name in case the given list
+// of errors is not empty and allows it otherwise.
+func validationResponse(kind string, name string, errs field.ErrorList) admission.Response {
+	if len(errs) == 0 {
+		return admission.Allowed("")
+	}
+	status := apierrors.NewInvalid(klyshkov1alpha1.GroupVersion.WithKind(kind).GroupKind(), name, errs).ErrStatus
+	return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{Allowed: false, Result: &status}}
+}
+
+//+kubebuilder:webhook:path=/mutate-klyshko-carbnyestack-io-v1alpha1-tuplegenerationjob,mutating=true,failurePolicy=fail,sideEffects=None,groups=klyshko.carbnyestack.io,resources=tuplegenerationjobs,verbs=create,versions=v1alpha1,name=mtuplegenerationjob.klyshko.carbnyestack.io,admissionReviewVersions=v1
+
+// TupleGenerationJobDefaulter assigns a random ID to TupleGenerationJobs created without one.
+type TupleGenerationJobDefaulter struct {
+	decoderInjector
+}
+
+// Handle defaults the TupleGenerationJob contained in the given admission request.
+func (d *TupleGenerationJobDefaulter) Handle(_ context.Context, req admission.Request) admission.Response {
+	job := &klyshkov1alpha1.TupleGenerationJob{}
+	if err := d.decoder.Decode(req, job); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if job.Spec.ID != "" {
+		return admission.Allowed("")
+	}
+	job.Spec.ID = uuid.New().String()
+	defaulted, err := json.Marshal(job)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, defaulted)
+}
+
+//+kubebuilder:webhook:path=/validate-klyshko-carbnyestack-io-v1alpha1-tuplegenerationjob,mutating=false,failurePolicy=fail,sideEffects=None,groups=klyshko.carbnyestack.io,resources=tuplegenerationjobs,verbs=create;update,versions=v1alpha1,name=vtuplegenerationjob.klyshko.carbnyestack.io,admissionReviewVersions=v1
+
+// TupleGenerationJobValidator rejects TupleGenerationJobs that can't be processed, i.e., jobs with an ID that is not a
+// UUID or that reference a TupleGenerator that does not exist or does not support the requested tuple type. The
+// generator reference is not checked for jobs created by the operator, as these have been admitted on the coordinating
+// VCP already, where the generator is available. In addition, the specification of a job can't be modified after
+// creation except for requesting its cancellation.
+type TupleGenerationJobValidator struct {
+	decoderInjector
+	Client client.Client
+
+	// OperatorUsername is the name of the user the operator authenticates as, e.g.,
+	// system:serviceaccount:klyshko-system:klyshko-controller-manager. Jobs created by other users are checked for
+	// their generator unless they are controlled by an existing job or scheduler. Empty in case unknown.
+	OperatorUsername string
+}
+
+// Handle validates the TupleGenerationJob contained in the given admission request.
+func (v *TupleGenerationJobValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	job := &klyshkov1alpha1.TupleGenerationJob{}
+	if err := v.decoder.Decode(req, job); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if req.Operation == admissionv1.Update {
+		old := &klyshkov1alpha1.TupleGenerationJob{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		return validationResponse("TupleGenerationJob", job.Name, validateJobUpdate(old, job))
+	}
+	errs, err := v.validateJob(ctx, job, req.UserInfo.Username)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return validationResponse("TupleGenerationJob", job.Name, errs)
+}
+
+// validateJob validates the ID and the generator reference of the given job created by the user with the given name.
+// The generator reference is validated only for jobs not created by the operator, i.e., jobs neither requested by the
+// operator nor controlled by an existing job or scheduler.
+func (v *TupleGenerationJobValidator) validateJob(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob, username string) (field.ErrorList, error) {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+	if _, err := uuid.Parse(job.Spec.ID); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("id"), job.Spec.ID,
+			"must be a UUID as it is used as the identifier of the generated tuple chunk (omit to have one assigned)"))
+	}
+	if v.OperatorUsername != "" && username == v.OperatorUsername {
+		return errs, nil
+	}
+	controlled, err := v.isControlledByKlyshko(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if controlled {
+		return errs, nil
+	}
+	generator := &klyshkov1alpha1.TupleGenerator{}
+	err = v.Client.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: job.Spec.Generator}, generator)
+	if apierrors.IsNotFound(err) {
+		return append(errs, field.NotFound(specPath.Child("generatorRef"), job.Spec.Generator)), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generator %v: %w", job.Spec.Generator, err)
+	}
+	if generator.Spec.GetTupleTypeSpec(job.Spec.Type) == nil {
+		var supported []string
+		for _, s := range generator.Spec.Supports {
+			supported = append(supported, s.Type)
+		}
+		errs = append(errs, field.Invalid(specPath.Child("type"), job.Spec.Type,
+			fmt.Sprintf("not supported by generator '%s' (supported types: %s)", generator.Name,
+				strings.Join(supported, ", "))))
+	}
+	return errs, nil
+}
+
+// isControlledByKlyshko checks whether the controller reference of the given job, if any, points to an existing
+// TupleGenerationJob or TupleGenerationScheduler, as is the case for shards and jobs created by schedulers.
+func (v *TupleGenerationJobValidator) isControlledByKlyshko(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob) (bool, error) {
+	ref := metav1.GetControllerOf(job)
+	if ref == nil || ref.APIVersion != klyshkov1alpha1.GroupVersion.String() {
+		return false, nil
+	}
+	var controller client.Object
+	switch ref.Kind {
+	case "TupleGenerationJob":
+		controller = &klyshkov1alpha1.TupleGenerationJob{}
+	case "TupleGenerationScheduler":
+		controller = &klyshkov1alpha1.TupleGenerationScheduler{}
+	default:
+		return false, nil
+	}
+	err := v.Client.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: ref.Name}, controller)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read controller %v of job %v: %w", ref.Name, job.Name, err)
+	}
+	return controller.GetUID() == ref.UID, nil
+}
+
+// validateJobUpdate rejects modifications of the specification of the given old job, except for requesting its
+// cancellation.
+func validateJobUpdate(old *klyshkov1alpha1.TupleGenerationJob, job *klyshkov1alpha1.TupleGenerationJob) field.ErrorList {
+	oldSpec := old.Spec.DeepCopy()
+	oldSpec.Cancel = job.Spec.Cancel
+	if equality.Semantic.DeepEqual(*oldSpec, job.Spec) {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(field.NewPath("spec"),
+		"is immutable except for the cancel field (create a new job instead)")}
+}
+
+//+kubebuilder:webhook:path=/validate-klyshko-carbnyestack-io-v1alpha1-tuplegenerationscheduler,mutating=false,failurePolicy=fail,sideEffects=None,groups=klyshko.carbnyestack.io,resources=tuplegenerationschedulers,verbs=create;update,versions=v1alpha1,name=vtuplegenerationscheduler.klyshko.carbnyestack.io,admissionReviewVersions=v1
+
+// TupleGenerationSchedulerValidator rejects TupleGenerationSchedulers declaring multiple policies for the same tuple
+// type, contradicting batch sizes, unknown scheduling strategies, or malformed generation windows.
+type TupleGenerationSchedulerValidator struct {
+	decoderInjector
+	Registry *SchedulingStrategyRegistry
+}
+
+// Handle validates the TupleGenerationScheduler contained in the given admission request.
+func (v *TupleGenerationSchedulerValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	scheduler := &klyshkov1alpha1.TupleGenerationScheduler{}
+	if err := v.decoder.Decode(req, scheduler); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	return validationResponse("TupleGenerationScheduler", scheduler.Name, v.validateScheduler(scheduler))
+}
+
+// validateScheduler validates the policies, the strategy and the windows of the given scheduler.
+func (v *TupleGenerationSchedulerValidator) validateScheduler(scheduler *klyshkov1alpha1.TupleGenerationScheduler) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+	seen := map[string]bool{}
+	for i, policy := range scheduler.Spec.TupleTypePolicies {
+		policyPath := specPath.Child("policies").Index(i)
+		if seen[policy.Type] {
+			errs = append(errs, field.Duplicate(policyPath.Child("type"), policy.Type))
+		}
+		seen[policy.Type] = true
+		if policy.MinBatchSize > 0 && policy.MaxBatchSize > 0 && policy.MinBatchSize > policy.MaxBatchSize {
+			errs = append(errs, field.Invalid(policyPath.Child("minBatchSize"), policy.MinBatchSize,
+				fmt.Sprintf("must not exceed maxBatchSize (%d)", policy.MaxBatchSize)))
+		}
+		if _, err := getCandidateGenerators(policy.GeneratorSelection, nil); err != nil {
+			errs = append(errs, field.Invalid(policyPath.Child("generatorSelection", "selector"),
+				policy.GeneratorSelection.Selector, err.Error()))
+		}
+	}
+	if v.Registry != nil {
+		if _, err := v.Registry.New(scheduler.Spec.Strategy); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("strategy"), scheduler.Spec.Strategy.Name, err.Error()))
+		}
+	}
+	if _, err := time.LoadLocation(scheduler.Spec.TimeZone); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("timeZone"), scheduler.Spec.TimeZone,
+			"must be a time zone of the IANA time zone database, e.g., Europe/Berlin"))
+	}
+	errs = append(errs, validateWindows(specPath.Child("windows"), scheduler.Spec.Windows)...)
+	return append(errs, validateWindows(specPath.Child("blackouts"), scheduler.Spec.Blackouts)...)
+}
+
+// validateWindows validates the schedules of the given windows located at the given path.
+func validateWindows(path *field.Path, windows []klyshkov1alpha1.TimeWindow) field.ErrorList {
+	var errs field.ErrorList
+	for i, window := range windows {
+		if _, err := cron.ParseStandard(window.Schedule); err != nil {
+			errs = append(errs, field.Invalid(path.Index(i).Child("schedule"), window.Schedule,
+				fmt.Sprintf("must be a cron expression, e.g., '0 22 * * *': %v", err)))
+		}
+	}
+	return errs
+}
+
+//+kubebuilder:webhook:path=/validate-klyshko-carbnyestack-io-v1alpha1-tuplegenerator,mutating=false,failurePolicy=fail,sideEffects=None,groups=klyshko.carbnyestack.io,resources=tuplegenerators,verbs=create;update,versions=v1alpha1,name=vtuplegenerator.klyshko.carbnyestack.io,admissionReviewVersions=v1
+
+// TupleGeneratorValidator rejects TupleGenerators without an image or declaring the same tuple type multiple times.
+type TupleGeneratorValidator struct {
+	decoderInjector
+}
+
+// Handle validates the TupleGenerator contained in the given admission request.
+func (v *TupleGeneratorValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	generator := &klyshkov1alpha1.TupleGenerator{}
+	if err := v.decoder.Decode(req, generator); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	return validationResponse("TupleGenerator", generator.Name, validateGenerator(generator))
+}
+
+// validateGenerator validates the image and the supported tuple types of the given generator.
+func validateGenerator(generator *klyshkov1alpha1.TupleGenerator) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+	if generator.Spec.Template.Spec.Container.Image == "" {
+		errs = append(errs, field.Required(specPath.Child("template", "spec", "container", "image"),
+			"the generator image must be given"))
+	}
+	seen := map[string]bool{}
+	for i, support := range generator.Spec.Supports {
+		if seen[support.Type] {
+			errs = append(errs, field.Duplicate(specPath.Child("supports").Index(i).Child("type"), support.Type))
+		}
+		seen[support.Type] = true
+	}
+	return errs
+}
+
+//+kubebuilder:webhook:path=/validate-klyshko-carbnyestack-io-v1alpha1-tuplegenerationtask,mutating=false,failurePolicy=fail,sideEffects=None,groups=klyshko.carbnyestack.io,resources=tuplegenerationtasks,verbs=create;update,versions=v1alpha1,name=vtuplegenerationtask.klyshko.carbnyestack.io,admissionReviewVersions=v1
+
+// TupleGenerationTaskValidator rejects TupleGenerationTasks for players not taking part in the VCP or named
+// inconsistently with their player. In addition, the specification of a task can't be modified after creation.
+type TupleGenerationTaskValidator struct {
+	decoderInjector
+	Client client.Client
+}
+
+// Handle validates the TupleGenerationTask contained in the given admission request.
+func (v *TupleGenerationTaskValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	task := &klyshkov1alpha1.TupleGenerationTask{}
+	if err := v.decoder.Decode(req, task); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if req.Operation == admissionv1.Update {
+		old := &klyshkov1alpha1.TupleGenerationTask{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if old.Spec != task.Spec {
+			return validationResponse("TupleGenerationTask", task.Name, field.ErrorList{
+				field.Forbidden(field.NewPath("spec"), "is immutable")})
+		}
+		return admission.Allowed("")
+	}
+	numberOfVCPs, err := numberOfVCPs(ctx, &v.Client, task.Namespace)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError,
+			fmt.Errorf("can't read playerCount from VCP configuration: %w", err))
+	}
+	return validationResponse("TupleGenerationTask", task.Name, validateTask(task, numberOfVCPs))
+}
+
+// validateTask validates the player of the given task for a VCP consisting of the given number of VCPs.
+func validateTask(task *klyshkov1alpha1.TupleGenerationTask, numberOfVCPs uint) field.ErrorList {
+	var errs field.ErrorList
+	playerIDPath := field.NewPath("spec", "playerId")
+	if task.Spec.PlayerID >= numberOfVCPs {
+		errs = append(errs, field.Invalid(playerIDPath, int(task.Spec.PlayerID),
+			fmt.Sprintf("must be less than the number of VCPs (%d)", numberOfVCPs)))
+	}
+	suffix := "-" + strconv.Itoa(int(task.Spec.PlayerID))
+	if !strings.HasSuffix(task.Name, suffix) || len(task.Name) == len(suffix) {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), task.Name,
+			fmt.Sprintf("must be the name of the job followed by '%s'", suffix)))
+	}
+	return errs
+}
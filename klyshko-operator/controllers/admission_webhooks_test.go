@@ -0,0 +1,269 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"time"
+)
+
+var _ = Describe("Admission webhooks", func() {
+	const jobID = "6b1f7a3e-0a55-4bd4-8f0e-1c1e4c1e2a7d"
+
+	var decoder *admission.Decoder
+	BeforeEach(func() {
+		var err error
+		decoder, err = admission.NewDecoder(newFakeSchedulerReconciler(nil).Scheme)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	request := func(operation admissionv1.Operation, obj client.Object, old client.Object) admission.Request {
+		raw := func(o client.Object) runtime.RawExtension {
+			if o == nil {
+				return runtime.RawExtension{}
+			}
+			encoded, err := json.Marshal(o)
+			Expect(err).NotTo(HaveOccurred())
+			return runtime.RawExtension{Raw: encoded}
+		}
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			Object:    raw(obj),
+			OldObject: raw(old),
+		}}
+	}
+	generator := &klyshkov1alpha1.TupleGenerator{
+		ObjectMeta: metav1.ObjectMeta{Name: "generator", Namespace: "default"},
+		Spec: klyshkov1alpha1.TupleGeneratorSpec{
+			Template: klyshkov1alpha1.TupleGeneratorPodTemplateSpec{Spec: klyshkov1alpha1.TupleGeneratorPodSpec{
+				Container: klyshkov1alpha1.TupleGeneratorContainer{Image: "generator:latest"},
+			}},
+			Supports: []klyshkov1alpha1.TupleTypeSpec{
+				{Type: "MULTIPLICATION_TRIPLE_GFP", BatchSize: 1000},
+				{Type: "BIT_GFP", BatchSize: 1000},
+			},
+		},
+	}
+	newJob := func(id string, tupleType string, generator string) *klyshkov1alpha1.TupleGenerationJob {
+		return &klyshkov1alpha1.TupleGenerationJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "default"},
+			Spec:       klyshkov1alpha1.TupleGenerationJobSpec{ID: id, Type: tupleType, Count: 1000, Generator: generator},
+		}
+	}
+
+	Describe("Defaulting jobs", func() {
+		It("assigns a UUID to jobs without an ID", func() {
+			d := &TupleGenerationJobDefaulter{}
+			Expect(d.InjectDecoder(decoder)).To(Succeed())
+			response := d.Handle(context.Background(), request(admissionv1.Create, newJob("", "BIT_GFP", "generator"), nil))
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patches).To(HaveLen(1))
+			Expect(response.Patches[0].Path).To(Equal("/spec/id"))
+			Expect(response.Patches[0].Value).To(MatchRegexp("^[0-9a-f-]{36}$"))
+
+			response = d.Handle(context.Background(), request(admissionv1.Create, newJob(jobID, "BIT_GFP", "generator"), nil))
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patches).To(BeEmpty())
+		})
+	})
+
+	Describe("Validating jobs", func() {
+		var v *TupleGenerationJobValidator
+		BeforeEach(func() {
+			v = &TupleGenerationJobValidator{Client: newFakeSchedulerReconciler(nil, generator.DeepCopy()).Client}
+			Expect(v.InjectDecoder(decoder)).To(Succeed())
+		})
+
+		It("accepts jobs referencing a generator supporting the tuple type", func() {
+			response := v.Handle(context.Background(), request(admissionv1.Create, newJob(jobID, "BIT_GFP", "generator"), nil))
+			Expect(response.Allowed).To(BeTrue())
+		})
+
+		It("rejects jobs with an ID that is not a UUID", func() {
+			response := v.Handle(context.Background(), request(admissionv1.Create, newJob("job-1", "BIT_GFP", "generator"), nil))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("spec.id: Invalid value: \"job-1\": must be a UUID"))
+		})
+
+		It("rejects jobs referencing a missing generator", func() {
+			response := v.Handle(context.Background(), request(admissionv1.Create, newJob(jobID, "BIT_GFP", "missing"), nil))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("spec.generatorRef: Not found: \"missing\""))
+		})
+
+		It("doesn't check the generator of jobs created by the operator", func() {
+			v.OperatorUsername = "system:serviceaccount:klyshko-system:klyshko-controller-manager"
+			mirrored := newJob(jobID, "BIT_GFP", "missing")
+			mirrored.Labels = map[string]string{MirroredJobLabel: "true"}
+			byOperator := request(admissionv1.Create, mirrored, nil)
+			byOperator.UserInfo.Username = v.OperatorUsername
+			Expect(v.Handle(context.Background(), byOperator).Allowed).To(BeTrue())
+			byUser := request(admissionv1.Create, mirrored, nil)
+			byUser.UserInfo.Username = "alice"
+			Expect(v.Handle(context.Background(), byUser).Allowed).To(BeFalse())
+
+			mirrored.Spec.ID = "job-1"
+			byOperator = request(admissionv1.Create, mirrored, nil)
+			byOperator.UserInfo.Username = v.OperatorUsername
+			Expect(v.Handle(context.Background(), byOperator).Allowed).To(BeFalse())
+		})
+
+		It("doesn't check the generator of jobs controlled by an existing job or scheduler", func() {
+			sharded := newJob(jobID, "BIT_GFP", "generator")
+			sharded.Name, sharded.UID = "sharded", "sharded-uid"
+			scheduler := newTestScheduler("BIT_GFP")
+			v.Client = newFakeSchedulerReconciler(nil, generator.DeepCopy(), sharded, scheduler).Client
+			controlledBy := func(kind string, name string, uid types.UID) *klyshkov1alpha1.TupleGenerationJob {
+				job := newJob(jobID, "BIT_GFP", "missing")
+				controller := true
+				job.OwnerReferences = []metav1.OwnerReference{{APIVersion: klyshkov1alpha1.GroupVersion.String(),
+					Kind: kind, Name: name, UID: uid, Controller: &controller}}
+				return job
+			}
+
+			for _, job := range []*klyshkov1alpha1.TupleGenerationJob{
+				controlledBy("TupleGenerationJob", "sharded", "sharded-uid"),
+				controlledBy("TupleGenerationScheduler", scheduler.Name, scheduler.UID),
+			} {
+				Expect(v.Handle(context.Background(), request(admissionv1.Create, job, nil)).Allowed).To(BeTrue())
+			}
+			for _, job := range []*klyshkov1alpha1.TupleGenerationJob{
+				controlledBy("TupleGenerationJob", "missing", "uid"),
+				controlledBy("TupleGenerationJob", "sharded", "other-uid"),
+				controlledBy("TupleGenerator", "generator", ""),
+			} {
+				Expect(v.Handle(context.Background(), request(admissionv1.Create, job, nil)).Allowed).To(BeFalse())
+			}
+		})
+
+		It("rejects jobs for tuple types not supported by the generator", func() {
+			response := v.Handle(context.Background(), request(admissionv1.Create, newJob(jobID, "BIT_GF2N", "generator"), nil))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("not supported by generator 'generator' " +
+				"(supported types: MULTIPLICATION_TRIPLE_GFP, BIT_GFP)"))
+		})
+
+		It("allows to cancel jobs but rejects other modifications", func() {
+			old := newJob(jobID, "BIT_GFP", "generator")
+			cancelled := old.DeepCopy()
+			cancelled.Spec.Cancel = true
+			Expect(v.Handle(context.Background(), request(admissionv1.Update, cancelled, old)).Allowed).To(BeTrue())
+
+			modified := cancelled.DeepCopy()
+			modified.Spec.Count = 2000
+			response := v.Handle(context.Background(), request(admissionv1.Update, modified, old))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("spec: Forbidden: is immutable except for the cancel field"))
+		})
+	})
+
+	Describe("Validating schedulers", func() {
+		var v *TupleGenerationSchedulerValidator
+		BeforeEach(func() {
+			v = &TupleGenerationSchedulerValidator{Registry: NewSchedulingStrategyRegistry()}
+			Expect(v.InjectDecoder(decoder)).To(Succeed())
+		})
+
+		It("accepts valid schedulers", func() {
+			scheduler := newTestScheduler("BIT_GFP")
+			scheduler.Spec.TimeZone = "Europe/Berlin"
+			scheduler.Spec.Windows = []klyshkov1alpha1.TimeWindow{
+				{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 6 * time.Hour}},
+			}
+			Expect(v.Handle(context.Background(), request(admissionv1.Create, scheduler, nil)).Allowed).To(BeTrue())
+		})
+
+		It("rejects multiple policies for the same tuple type", func() {
+			scheduler := newTestScheduler("BIT_GFP")
+			scheduler.Spec.TupleTypePolicies = append(scheduler.Spec.TupleTypePolicies,
+				klyshkov1alpha1.TupleTypePolicy{Type: "BIT_GFP", Threshold: 10, Priority: 1})
+			response := v.Handle(context.Background(), request(admissionv1.Create, scheduler, nil))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("spec.policies[1].type: Duplicate value: \"BIT_GFP\""))
+		})
+
+		It("rejects unknown strategies, malformed windows and contradicting batch sizes", func() {
+			scheduler := newTestScheduler("BIT_GFP")
+			scheduler.Spec.TupleTypePolicies[0].MinBatchSize = 2000
+			scheduler.Spec.TupleTypePolicies[0].MaxBatchSize = 1000
+			scheduler.Spec.Strategy.Name = "Unknown"
+			scheduler.Spec.TimeZone = "Mars/Olympus"
+			scheduler.Spec.Blackouts = []klyshkov1alpha1.TimeWindow{{Schedule: "every night"}}
+			response := v.Handle(context.Background(), request(admissionv1.Create, scheduler, nil))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(SatisfyAll(
+				ContainSubstring("spec.policies[0].minBatchSize: Invalid value: 2000: must not exceed maxBatchSize (1000)"),
+				ContainSubstring("spec.strategy: Invalid value: \"Unknown\": unknown scheduling strategy 'Unknown'"),
+				ContainSubstring("spec.timeZone: Invalid value: \"Mars/Olympus\""),
+				ContainSubstring("spec.blackouts[0].schedule: Invalid value: \"every night\""),
+			))
+		})
+	})
+
+	Describe("Validating generators", func() {
+		It("rejects generators without image or declaring a tuple type multiple times", func() {
+			v := &TupleGeneratorValidator{}
+			Expect(v.InjectDecoder(decoder)).To(Succeed())
+			Expect(v.Handle(context.Background(), request(admissionv1.Create, generator, nil)).Allowed).To(BeTrue())
+
+			invalid := generator.DeepCopy()
+			invalid.Spec.Template.Spec.Container.Image = ""
+			invalid.Spec.Supports = append(invalid.Spec.Supports,
+				klyshkov1alpha1.TupleTypeSpec{Type: "BIT_GFP", BatchSize: 10})
+			response := v.Handle(context.Background(), request(admissionv1.Create, invalid, nil))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(SatisfyAll(
+				ContainSubstring("spec.template.spec.container.image: Required value"),
+				ContainSubstring("spec.supports[2].type: Duplicate value: \"BIT_GFP\""),
+			))
+		})
+	})
+
+	Describe("Validating tasks", func() {
+		var v *TupleGenerationTaskValidator
+		BeforeEach(func() {
+			v = &TupleGenerationTaskValidator{Client: newFakeSchedulerReconciler(nil).Client}
+			Expect(v.InjectDecoder(decoder)).To(Succeed())
+		})
+		newTask := func(name string, playerID uint) *klyshkov1alpha1.TupleGenerationTask {
+			return &klyshkov1alpha1.TupleGenerationTask{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec:       klyshkov1alpha1.TupleGenerationTaskSpec{PlayerID: playerID},
+			}
+		}
+
+		It("accepts tasks of players taking part in the VCP", func() {
+			Expect(v.Handle(context.Background(), request(admissionv1.Create, newTask("job-1", 1), nil)).Allowed).To(BeTrue())
+		})
+
+		It("rejects tasks of unknown players or named inconsistently", func() {
+			response := v.Handle(context.Background(), request(admissionv1.Create, newTask("job-1", 2), nil))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(SatisfyAll(
+				ContainSubstring("spec.playerId: Invalid value: 2: must be less than the number of VCPs (2)"),
+				ContainSubstring("metadata.name: Invalid value: \"job-1\": must be the name of the job followed by '-2'"),
+			))
+		})
+
+		It("rejects modifications of the player", func() {
+			response := v.Handle(context.Background(), request(admissionv1.Update, newTask("job-0", 1), newTask("job-0", 0)))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("spec: Forbidden: is immutable"))
+		})
+	})
+})
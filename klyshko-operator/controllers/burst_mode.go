@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+// MaxBurstEpisodes is the number of most recent burst episodes kept in the status of a scheduler.
+const MaxBurstEpisodes = 10
+
+// isBurstEnabled checks whether the given scheduler may exceed its regular concurrency.
+func isBurstEnabled(scheduler *klyshkov1alpha1.TupleGenerationScheduler) bool {
+	return scheduler.Spec.BurstConcurrency > scheduler.Spec.Concurrency
+}
+
+// getCriticalTupleTypes returns the tuple types of the given policies for which the number of tuples available
+// according to the given telemetry is below the emergency threshold. In-flight tuples are deliberately not taken into
+// account, i.e., a tuple type stays critical until generated tuples actually became available.
+func getCriticalTupleTypes(policies []klyshkov1alpha1.TupleTypePolicy, telemetry castor.Telemetry) []string {
+	var critical []string
+	for _, policy := range policies {
+		if policy.EmergencyThreshold > 0 && getAvailableTuples(telemetry, policy.Type) < policy.EmergencyThreshold {
+			critical = append(critical, policy.Type)
+		}
+	}
+	return critical
+}
+
+// trackBurstEpisode updates the burst episodes and the bursting condition of the given scheduler given the tuple
+// types currently below their emergency threshold. A new episode starts in case there are critical tuple types and
+// burst mode is enabled, and the ongoing one ends otherwise. Returns whether the scheduler is in burst mode.
+func trackBurstEpisode(scheduler *klyshkov1alpha1.TupleGenerationScheduler, critical []string, now time.Time) bool {
+	labels := []string{scheduler.Namespace, scheduler.Name}
+	episodes := scheduler.Status.BurstEpisodes
+	var ongoing *klyshkov1alpha1.BurstEpisode
+	if len(episodes) > 0 && episodes[len(episodes)-1].EndTime == nil {
+		ongoing = &episodes[len(episodes)-1]
+	}
+	bursting := isBurstEnabled(scheduler) && len(critical) > 0
+	switch {
+	case bursting && ongoing == nil:
+		episodes = append(episodes, klyshkov1alpha1.BurstEpisode{
+			StartTime:  metav1.NewTime(now),
+			TupleTypes: critical,
+		})
+		if len(episodes) > MaxBurstEpisodes {
+			episodes = episodes[len(episodes)-MaxBurstEpisodes:]
+		}
+		schedulerBurstEpisodes.WithLabelValues(labels...).Inc()
+	case bursting:
+		for _, tupleType := range critical {
+			if !containsString(ongoing.TupleTypes, tupleType) {
+				ongoing.TupleTypes = append(ongoing.TupleTypes, tupleType)
+			}
+		}
+	case ongoing != nil:
+		end := metav1.NewTime(now)
+		ongoing.EndTime = &end
+	}
+	scheduler.Status.BurstEpisodes = episodes
+
+	if !isBurstEnabled(scheduler) {
+		meta.RemoveStatusCondition(&scheduler.Status.Conditions, klyshkov1alpha1.SchedulerBursting)
+		schedulerBursting.DeleteLabelValues(labels...)
+		return false
+	}
+	if bursting {
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerBursting, metav1.ConditionTrue, "BelowEmergencyThreshold",
+			fmt.Sprintf("Tuple types %v are below their emergency threshold", critical))
+		schedulerBursting.WithLabelValues(labels...).Set(1)
+	} else {
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerBursting, metav1.ConditionFalse, "AboveEmergencyThreshold",
+			"No tuple type is below its emergency threshold")
+		schedulerBursting.WithLabelValues(labels...).Set(0)
+	}
+	return bursting
+}
+
+// recordBurstJob accounts a job created beyond the regular concurrency to the ongoing burst episode of the given
+// scheduler.
+func recordBurstJob(scheduler *klyshkov1alpha1.TupleGenerationScheduler) {
+	episodes := scheduler.Status.BurstEpisodes
+	if len(episodes) > 0 && episodes[len(episodes)-1].EndTime == nil {
+		episodes[len(episodes)-1].Jobs++
+	}
+	schedulerBurstJobs.WithLabelValues(scheduler.Namespace, scheduler.Name).Inc()
+}
+
+// getEmergencyPolicies returns the given policies for tuple types that are below their emergency threshold according
+// to the given tuple type states.
+func getEmergencyPolicies(policies []klyshkov1alpha1.TupleTypePolicy, states []klyshkov1alpha1.TupleTypeStatus) []klyshkov1alpha1.TupleTypePolicy {
+	emergency := map[string]bool{}
+	for _, state := range states {
+		emergency[state.Type] = state.Emergency
+	}
+	var filtered []klyshkov1alpha1.TupleTypePolicy
+	for _, policy := range policies {
+		if emergency[policy.Type] {
+			filtered = append(filtered, policy)
+		}
+	}
+	return filtered
+}
+
+// containsString checks whether the given string is contained in the given slice.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+var _ = Describe("Tracking burst episodes", func() {
+	const TupleTypeA = "A"
+	const TupleTypeB = "B"
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var scheduler *klyshkov1alpha1.TupleGenerationScheduler
+	BeforeEach(func() {
+		scheduler = &klyshkov1alpha1.TupleGenerationScheduler{
+			ObjectMeta: metav1.ObjectMeta{Name: "burst", Namespace: "default"},
+			Spec: klyshkov1alpha1.TupleGenerationSchedulerSpec{
+				Concurrency:      1,
+				BurstConcurrency: 3,
+			},
+		}
+		forgetSchedulerMetrics(scheduler.Namespace, scheduler.Name)
+	})
+
+	It("determines the tuple types below their emergency threshold", func() {
+		policies := []klyshkov1alpha1.TupleTypePolicy{
+			{Type: TupleTypeA, Threshold: 1000, EmergencyThreshold: 100},
+			{Type: TupleTypeB, Threshold: 1000},
+		}
+		telemetry := castor.Telemetry{TupleMetrics: []castor.TupleMetrics{
+			{TupleType: TupleTypeA, Available: 99},
+			{TupleType: TupleTypeB, Available: 0},
+		}}
+		Expect(getCriticalTupleTypes(policies, telemetry)).To(Equal([]string{TupleTypeA}))
+		telemetry.TupleMetrics[0].Available = 100
+		Expect(getCriticalTupleTypes(policies, telemetry)).To(BeEmpty())
+	})
+
+	It("starts, extends and ends episodes", func() {
+		Expect(trackBurstEpisode(scheduler, []string{TupleTypeA}, start)).To(BeTrue())
+		recordBurstJob(scheduler)
+		Expect(trackBurstEpisode(scheduler, []string{TupleTypeA, TupleTypeB}, start.Add(time.Minute))).To(BeTrue())
+		recordBurstJob(scheduler)
+		Expect(trackBurstEpisode(scheduler, nil, start.Add(2*time.Minute))).To(BeFalse())
+		end := metav1.NewTime(start.Add(2 * time.Minute))
+		Expect(scheduler.Status.BurstEpisodes).To(Equal([]klyshkov1alpha1.BurstEpisode{{
+			StartTime:  metav1.NewTime(start),
+			EndTime:    &end,
+			TupleTypes: []string{TupleTypeA, TupleTypeB},
+			Jobs:       2,
+		}}))
+		Expect(testutil.ToFloat64(schedulerBurstEpisodes.WithLabelValues("default", "burst"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(schedulerBurstJobs.WithLabelValues("default", "burst"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(schedulerBursting.WithLabelValues("default", "burst"))).To(Equal(0.0))
+	})
+
+	It("keeps the most recent episodes only", func() {
+		for i := 0; i < MaxBurstEpisodes+2; i++ {
+			trackBurstEpisode(scheduler, []string{TupleTypeA}, start.Add(time.Duration(2*i)*time.Minute))
+			trackBurstEpisode(scheduler, nil, start.Add(time.Duration(2*i+1)*time.Minute))
+		}
+		Expect(scheduler.Status.BurstEpisodes).To(HaveLen(MaxBurstEpisodes))
+		Expect(scheduler.Status.BurstEpisodes[0].StartTime.Time).To(Equal(start.Add(4 * time.Minute)))
+	})
+
+	It("ends ongoing episodes in case burst mode gets disabled", func() {
+		trackBurstEpisode(scheduler, []string{TupleTypeA}, start)
+		scheduler.Spec.BurstConcurrency = 0
+		Expect(trackBurstEpisode(scheduler, []string{TupleTypeA}, start.Add(time.Minute))).To(BeFalse())
+		Expect(scheduler.Status.BurstEpisodes[0].EndTime).NotTo(BeNil())
+		Expect(scheduler.Status.Conditions).To(BeEmpty())
+	})
+})
@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -20,6 +20,9 @@ import (
 
 const vcpConfigMapName = "cs-vcp-config"
 
+// coordinatorPlayerID is the identifier of the player whose VCP coordinates the generation of tuples.
+const coordinatorPlayerID = 0
+
 func getVCPConfig(ctx context.Context, client *client.Client, namespace string) (v1.ConfigMap, error) {
 	name := types.NamespacedName{
 		Namespace: namespace,
@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022-2024 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -182,7 +182,8 @@ func (vcp *vcp) setupControllers(ctx context.Context, vcpID int, etcdClient *cli
 	castorClient := castor.NewClient(castorURL)
 	controllers := []Controller{
 		NewTupleGenerationJobReconciler(
-			k8sManager.GetClient(), k8sManager.GetScheme(), etcdClient, castorClient, k8sManager.GetLogger()),
+			k8sManager.GetClient(), k8sManager.GetAPIReader(), k8sManager.GetScheme(), etcdClient, castorClient,
+			k8sManager.GetLogger(), 0),
 		&TupleGenerationTaskReconciler{ // TODO Replace with constructors
 			Client:           k8sManager.GetClient(),
 			Scheme:           k8sManager.GetScheme(),
@@ -191,11 +192,8 @@ func (vcp *vcp) setupControllers(ctx context.Context, vcpID int, etcdClient *cli
 		},
 	}
 	if vcpID == 0 {
-		controllers = append(controllers, &TupleGenerationSchedulerReconciler{
-			Client:       k8sManager.GetClient(),
-			Scheme:       k8sManager.GetScheme(),
-			CastorClient: castorClient,
-		})
+		controllers = append(controllers, NewTupleGenerationSchedulerReconciler(
+			k8sManager.GetClient(), k8sManager.GetScheme(), etcdClient, castorClient, NewSchedulingStrategyRegistry(), false))
 	}
 	for _, controller := range controllers {
 		err := controller.SetupWithManager(k8sManager)
@@ -268,6 +266,11 @@ func setupCastorServiceResponders(numberOfAvailableTuples int, tupleType string)
 		"=~^http://cs-castor.default.svc.cluster.local:10100/intra-vcp/tuple-chunks/activate/.*",
 		httpmock.NewStringResponder(200, ""),
 	)
+	httpmock.RegisterResponder(
+		"GET",
+		"=~^http://cs-castor.default.svc.cluster.local:10100/intra-vcp/tuple-chunks/.*/status",
+		httpmock.NewStringResponder(200, `{"status":"LOCKED"}`),
+	)
 	telemetry := castor.Telemetry{TupleMetrics: []castor.TupleMetrics{
 		{
 			Available:       numberOfAvailableTuples,
@@ -313,25 +316,94 @@ var _ = Describe("In case of shortage of tuples", func() {
 			httpmock.DeactivateAndReset()
 		})
 
-		It("doesn't generate a job", func() {
-			// Ensure that no job is created on any of the VCPs
-			Consistently(func() bool {
+		It("generates a job using one of them", func() {
+			// Ensure that a job is created using one of the generators supporting the tuple type
+			Eventually(func() []klyshkov1alpha1.TupleGenerationJob {
 				jobList := &klyshkov1alpha1.TupleGenerationJobList{}
-				opts := []client.ListOption{
-					client.InNamespace(scheduler.Namespace),
+				err := vc.vcps[0].k8sClient.List(ctx, jobList, client.InNamespace(scheduler.Namespace))
+				if err != nil {
+					return nil
 				}
-				for i := 0; i < NumberOfVCPs; i++ {
-					err := vc.vcps[i].k8sClient.List(ctx, jobList, opts...)
-					if err != nil || len(jobList.Items) > 0 {
-						return false
-					}
+				return jobList.Items
+			}, Timeout, PollingInterval).Should(ContainElement(And(
+				HaveField("Spec.Type", ConflictingTupleType),
+				HaveField("Spec.Generator", BeElementOf("tuple-generator-a", "tuple-generator-b")),
+			)))
+		})
+
+		It("records the generator in the last decision", func() {
+			key := types.NamespacedName{Name: scheduler.Name, Namespace: scheduler.Namespace}
+			Eventually(func() string {
+				s := &klyshkov1alpha1.TupleGenerationScheduler{}
+				if err := vc.vcps[0].k8sClient.Get(ctx, key, s); err != nil || s.Status.LastDecision == nil {
+					return ""
 				}
-				return true
-			}).WithTimeout(Timeout).WithPolling(PollingInterval).Should(BeTrue())
+				return s.Status.LastDecision.Generator
+			}, Timeout, PollingInterval).Should(BeElementOf("tuple-generator-a", "tuple-generator-b"))
 		})
 	})
 })
 
+var _ = Describe("In case of jobs not controlled by the scheduler", func() {
+
+	var (
+		ctx       context.Context
+		cancel    context.CancelFunc
+		vc        *vc
+		scheduler *klyshkov1alpha1.TupleGenerationScheduler
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.TODO())
+		httpmock.Activate()
+		setupCastorServiceResponders(0, ValidTupleType)
+		var err error
+		vc, err = setupVC(ctx, NumberOfVCPs)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Create a job that is not controlled by any scheduler
+		jobID := uuid.New().String()
+		foreignJob := &klyshkov1alpha1.TupleGenerationJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foreign-" + jobID,
+				Namespace: SchedulerNamespace,
+			},
+			Spec: klyshkov1alpha1.TupleGenerationJobSpec{
+				ID:        jobID,
+				Type:      ValidTupleType,
+				Count:     TuplesPerJob,
+				Generator: "tuple-generator-a",
+			},
+		}
+		Expect(vc.vcps[0].k8sClient.Create(ctx, foreignJob)).Should(Succeed())
+
+		scheduler = createScheduler(ctx, vc)
+	})
+
+	AfterEach(func() {
+		cancel()
+		err := vc.teardown()
+		Expect(err).NotTo(HaveOccurred())
+		httpmock.DeactivateAndReset()
+	})
+
+	It("doesn't count them towards the concurrency limit", func() {
+		Eventually(func() bool {
+			jobList := &klyshkov1alpha1.TupleGenerationJobList{}
+			err := vc.vcps[0].k8sClient.List(ctx, jobList, client.InNamespace(scheduler.Namespace))
+			if err != nil {
+				return false
+			}
+			for _, job := range jobList.Items {
+				if metav1.IsControlledBy(&job, scheduler) {
+					return true
+				}
+			}
+			return false
+		}, Timeout, PollingInterval).Should(BeTrue())
+	})
+})
+
 var _ = Describe("Generating tuples", func() {
 
 	When("a scheduler is deployed", func() {
@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	"github.com/carbynestack/klyshko/logging"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sync"
+)
+
+// DeficitRoundRobinSchedulingStrategyName is the name under which the DeficitRoundRobinSchedulingStrategy is
+// registered.
+const DeficitRoundRobinSchedulingStrategyName = "DeficitRoundRobin"
+
+// DeficitRoundRobinSchedulingStrategy is a deterministic weighted-fair strategy. The eligible tuple types, i.e., those
+// below their threshold, are visited in the order of the policies given in the scheduler spec. Whenever a tuple type
+// is visited, its deficit counter is increased by its priority. Each scheduled job costs one unit of deficit and the
+// visited tuple type is served until its deficit is used up. Hence, over time each eligible tuple type receives a share
+// of jobs proportional to its priority and no tuple type starves. The deficit of a tuple type that is not eligible is
+// reset to zero.
+//
+// The deficit counters are kept across invocations of Schedule. As strategies are cached per scheduler, this results
+// in per-scheduler counters.
+type DeficitRoundRobinSchedulingStrategy struct {
+	mutex sync.Mutex
+	// deficits holds the deficit counter per tuple type.
+	deficits map[string]int
+	// current is the tuple type currently being served.
+	current string
+}
+
+// NewDeficitRoundRobinSchedulingStrategy creates a DeficitRoundRobinSchedulingStrategy. The strategy does not support
+// any parameters.
+func NewDeficitRoundRobinSchedulingStrategy(parameters map[string]string) (SchedulingStrategy, error) {
+	if err := checkParameters(parameters); err != nil {
+		return nil, err
+	}
+	return &DeficitRoundRobinSchedulingStrategy{
+		deficits: map[string]int{},
+	}, nil
+}
+
+// Deficits returns a copy of the current deficit counters by tuple type.
+func (s *DeficitRoundRobinSchedulingStrategy) Deficits() map[string]int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	deficits := make(map[string]int, len(s.deficits))
+	for tupleType, deficit := range s.deficits {
+		deficits[tupleType] = deficit
+	}
+	return deficits
+}
+
+// Schedule returns the tuple type selected by this strategy or `nil` if none is eligible. An error is returned in case
+// none of the eligible tuple types has a positive priority, as none of them would ever accumulate deficit.
+func (s *DeficitRoundRobinSchedulingStrategy) Schedule(
+	ctx context.Context, telemetry castor.Telemetry, policies []v1alpha1.TupleTypePolicy,
+	activeJobs []v1alpha1.TupleGenerationJob) (*string, error) {
+
+	logger := log.FromContext(ctx)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.deficits == nil {
+		s.deficits = map[string]int{}
+	}
+
+	// Compute aggregate of available and in-flight tuples
+	t := withInflightTuples(telemetry, activeJobs)
+	availableByType := map[string]int{}
+	for _, m := range t.TupleMetrics {
+		availableByType[m.TupleType] = m.Available
+	}
+
+	// Determine the eligible tuple types in policy order and reset the deficit of all others
+	eligible := make([]bool, len(policies))
+	anyEligible, anyPrioritized := false, false
+	for idx, policy := range policies {
+		available, exists := availableByType[policy.Type]
+		eligible[idx] = exists && available < policy.Threshold
+		if eligible[idx] {
+			anyEligible = true
+			anyPrioritized = anyPrioritized || policy.Priority > 0
+		} else {
+			delete(s.deficits, policy.Type)
+		}
+	}
+	for tupleType := range s.deficits {
+		if getPolicyIndex(policies, tupleType) < 0 {
+			delete(s.deficits, tupleType)
+		}
+	}
+	if !anyEligible {
+		logger.V(logging.DEBUG).Info("Above threshold for all tuple types")
+		s.current = ""
+		return nil, nil
+	}
+	if !anyPrioritized {
+		return nil, errors.New("none of the eligible tuple types has a positive priority")
+	}
+
+	// Serve the current tuple type while it has deficit left, otherwise advance to the next eligible one. As
+	// at least one eligible tuple type has a positive priority, this terminates eventually.
+	idx := getPolicyIndex(policies, s.current)
+	for idx < 0 || !eligible[idx] || s.deficits[policies[idx].Type] < 1 {
+		idx = (idx + 1) % len(policies)
+		if eligible[idx] {
+			s.deficits[policies[idx].Type] += policies[idx].Priority
+		}
+	}
+	s.current = policies[idx].Type
+	s.deficits[s.current]--
+	logger.V(logging.DEBUG).Info("Selected tuple type", "TupleType", s.current, "Deficits", s.deficits)
+	selected := s.current
+	return &selected, nil
+}
+
+// getPolicyIndex returns the index of the policy for the given tuple type or -1 if there is none.
+func getPolicyIndex(policies []v1alpha1.TupleTypePolicy, tupleType string) int {
+	for idx, policy := range policies {
+		if policy.Type == tupleType {
+			return idx
+		}
+	}
+	return -1
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package controllers
+
+import (
+	"context"
+	"github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Context("DeficitRoundRobinSchedulingStrategy", func() {
+
+	// Test data
+	const TupleTypeA = "A"
+	const TupleTypeB = "B"
+	const TupleTypeC = "C"
+	policies := []v1alpha1.TupleTypePolicy{
+		{Type: TupleTypeA, Threshold: 1000, Priority: 1},
+		{Type: TupleTypeB, Threshold: 1000, Priority: 2},
+		{Type: TupleTypeC, Threshold: 1000, Priority: 1},
+	}
+	telemetryWith := func(availableA, availableB, availableC int) castor.Telemetry {
+		return castor.Telemetry{
+			TupleMetrics: []castor.TupleMetrics{
+				{Available: availableC, TupleType: TupleTypeC},
+				{Available: availableB, TupleType: TupleTypeB},
+				{Available: availableA, TupleType: TupleTypeA},
+			},
+		}
+	}
+	var noActiveJobs []v1alpha1.TupleGenerationJob
+	var strategy *DeficitRoundRobinSchedulingStrategy
+	schedule := func(telemetry castor.Telemetry, rounds int) []string {
+		var selected []string
+		for i := 0; i < rounds; i++ {
+			tupleType, err := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+			Expect(err).NotTo(HaveOccurred())
+			if tupleType == nil {
+				selected = append(selected, "")
+			} else {
+				selected = append(selected, *tupleType)
+			}
+		}
+		return selected
+	}
+
+	BeforeEach(func() {
+		s, err := NewDeficitRoundRobinSchedulingStrategy(nil)
+		Expect(err).NotTo(HaveOccurred())
+		strategy = s.(*DeficitRoundRobinSchedulingStrategy)
+	})
+
+	Describe("Selecting tuple types", func() {
+
+		When("there is no tuple type below the threshold", func() {
+			It("returns nil and keeps no deficits", func() {
+				Expect(schedule(telemetryWith(5000, 5000, 5000), 1)).To(Equal([]string{""}))
+				Expect(strategy.Deficits()).To(BeEmpty())
+			})
+		})
+
+		When("all tuple types are below the threshold", func() {
+			It("serves them in policy order proportional to their priorities", func() {
+				Expect(schedule(telemetryWith(0, 0, 0), 8)).To(Equal([]string{
+					TupleTypeA, TupleTypeB, TupleTypeB, TupleTypeC,
+					TupleTypeA, TupleTypeB, TupleTypeB, TupleTypeC,
+				}))
+			})
+			It("keeps the deficit counters across invocations", func() {
+				schedule(telemetryWith(0, 0, 0), 2)
+				Expect(strategy.Deficits()).To(Equal(map[string]int{TupleTypeA: 0, TupleTypeB: 1}))
+			})
+		})
+
+		When("a tuple type becomes ineligible", func() {
+			It("resets its deficit and skips it", func() {
+				schedule(telemetryWith(0, 0, 0), 2)
+				Expect(schedule(telemetryWith(0, 5000, 0), 3)).To(Equal([]string{TupleTypeC, TupleTypeA, TupleTypeC}))
+				Expect(strategy.Deficits()).NotTo(HaveKey(TupleTypeB))
+			})
+		})
+
+		When("no eligible tuple type has a positive priority", func() {
+			It("fails instead of looking for deficit forever", func() {
+				unprioritized := []v1alpha1.TupleTypePolicy{
+					{Type: TupleTypeA, Threshold: 1000, Priority: 0},
+					{Type: TupleTypeB, Threshold: 1000, Priority: 1},
+				}
+				_, err := strategy.Schedule(context.Background(), telemetryWith(0, 5000, 0), unprioritized, noActiveJobs)
+				Expect(err).To(MatchError(ContainSubstring("positive priority")))
+				selected, err := strategy.Schedule(context.Background(), telemetryWith(0, 0, 0), unprioritized, noActiveJobs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*selected).To(Equal(TupleTypeB))
+			})
+		})
+
+		It("produces the same schedule for separate instances", func() {
+			first := schedule(telemetryWith(0, 0, 0), 10)
+			s, err := NewSchedulingStrategyRegistry().New(
+				v1alpha1.SchedulingStrategySpec{Name: DeficitRoundRobinSchedulingStrategyName})
+			Expect(err).NotTo(HaveOccurred())
+			strategy = s.(*DeficitRoundRobinSchedulingStrategy)
+			Expect(schedule(telemetryWith(0, 0, 0), 10)).To(Equal(first))
+		})
+	})
+
+	It("does not accept parameters", func() {
+		_, err := NewDeficitRoundRobinSchedulingStrategy(map[string]string{"quantum": "1"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -17,6 +17,9 @@ import (
 
 const rosterKey = "/klyshko/roster"
 
+// telemetryKey is the key prefix used to store the telemetry data published by the players.
+const telemetryKey = "/klyshko/telemetry"
+
 // Key is a key for data stored in an etcd cluster.
 type Key interface {
 	ToEtcdKey() string
@@ -55,6 +58,28 @@ func (k RosterEntryKey) String() string {
 	return k.ToEtcdKey()
 }
 
+// TelemetryKey is a Key referencing the telemetry data published by a player for the given namespace. The keys of the
+// telemetry data published by all players for a namespace share the prefix returned by TelemetryPrefix.
+type TelemetryKey struct {
+	Namespace string
+	PlayerID  uint
+}
+
+// TelemetryPrefix returns the common prefix of the telemetry keys of all players for the given namespace.
+func TelemetryPrefix(namespace string) string {
+	return fmt.Sprintf("%s/%s/", telemetryKey, namespace)
+}
+
+// ToEtcdKey converts TelemetryKey k to an etcd key.
+func (k TelemetryKey) ToEtcdKey() string {
+	return fmt.Sprintf("%s%d", TelemetryPrefix(k.Namespace), k.PlayerID)
+}
+
+// String returns a string representation of TelemetryKey k.
+func (k TelemetryKey) String() string {
+	return k.ToEtcdKey()
+}
+
 var etcdRosterKeyPattern = regexp.MustCompile("^" + rosterKey + "/(?P<namespace>(\\w|-)+)/(?P<jobName>(\\w|-)+)(?:/(?P<localPlayerID>\\d+))?$")
 
 func etcdKeyParts(s string) map[string]string {
@@ -0,0 +1,197 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"time"
+)
+
+// failureKey identifies the subject failures are tracked for, i.e., a tuple type or the combination of a tuple type
+// and a generator. The generator is empty in the former case.
+type failureKey struct {
+	tupleType string
+	generator string
+}
+
+// failureTracker maintains the failure state of the subjects jobs are scheduled for according to a failure policy.
+type failureTracker struct {
+	policy   klyshkov1alpha1.FailurePolicySpec
+	failures map[failureKey]*klyshkov1alpha1.FailureStatus
+}
+
+// newFailureTracker creates a failure tracker for the given failure policy initialized with the given failure states.
+func newFailureTracker(policy klyshkov1alpha1.FailurePolicySpec, failures []klyshkov1alpha1.FailureStatus) *failureTracker {
+	t := &failureTracker{
+		policy:   policy,
+		failures: map[failureKey]*klyshkov1alpha1.FailureStatus{},
+	}
+	for _, f := range failures {
+		f := f.DeepCopy()
+		t.failures[failureKey{tupleType: f.Type, generator: f.Generator}] = f
+	}
+	return t
+}
+
+// observe takes the finished jobs out of the given ones into account that are not among the given observed jobs yet.
+// Jobs are identified by their UID and processed in the order they finished. Returns the observed jobs that still
+// exist, i.e., are among the given jobs, including the ones observed right now.
+func (t *failureTracker) observe(jobs []klyshkov1alpha1.TupleGenerationJob, observed []klyshkov1alpha1.ObservedJob) []klyshkov1alpha1.ObservedJob {
+	known := map[types.UID]klyshkov1alpha1.ObservedJob{}
+	for _, o := range observed {
+		known[o.UID] = o
+	}
+	var retained []klyshkov1alpha1.ObservedJob
+	var finished []klyshkov1alpha1.TupleGenerationJob
+	for _, job := range jobs {
+		if o, ok := known[job.UID]; ok {
+			retained = append(retained, o)
+		} else if job.Status.State.IsDone() {
+			finished = append(finished, job)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		a, b := finished[i].Status.LastStateTransitionTime, finished[j].Status.LastStateTransitionTime
+		if a.Equal(&b) {
+			return finished[i].Name < finished[j].Name
+		}
+		return a.Before(&b)
+	})
+	for _, job := range finished {
+		retained = append(retained, klyshkov1alpha1.ObservedJob{
+			Name: job.Name,
+			UID:  job.UID,
+			Time: job.Status.LastStateTransitionTime,
+		})
+		// Cancelled jobs say nothing about whether generating tuples works
+		if job.Status.State == klyshkov1alpha1.JobCancelled {
+			continue
+		}
+		for _, key := range []failureKey{{tupleType: job.Spec.Type}, {tupleType: job.Spec.Type, generator: job.Spec.Generator}} {
+			t.record(key, job.Status.State == klyshkov1alpha1.JobFailed, job.Status.LastStateTransitionTime)
+		}
+	}
+	return retained
+}
+
+// record updates the failure state of the given subject with the outcome of a job finished at the given time.
+func (t *failureTracker) record(key failureKey, failed bool, finished metav1.Time) {
+	f, exists := t.failures[key]
+	if !failed {
+		// A successful job closes the circuit breaker and resets the backoff
+		delete(t.failures, key)
+		return
+	}
+	if !exists {
+		f = &klyshkov1alpha1.FailureStatus{
+			Type:      key.tupleType,
+			Generator: key.generator,
+			State:     klyshkov1alpha1.CircuitBreakerClosed,
+		}
+		t.failures[key] = f
+	}
+	f.ConsecutiveFailures++
+	f.LastFailureTime = finished.DeepCopy()
+	if f.State == klyshkov1alpha1.CircuitBreakerHalfOpen ||
+		(t.policy.FailureThreshold > 0 && f.ConsecutiveFailures >= t.policy.FailureThreshold) {
+		f.State = klyshkov1alpha1.CircuitBreakerOpen
+		retry := metav1.NewTime(finished.Add(time.Duration(t.policy.CoolDownSeconds) * time.Second))
+		f.RetryTime = &retry
+		return
+	}
+	retry := metav1.NewTime(finished.Add(t.backoff(f.ConsecutiveFailures)))
+	f.RetryTime = &retry
+}
+
+// backoff computes the backoff after the given number of consecutive failures.
+func (t *failureTracker) backoff(consecutiveFailures int) time.Duration {
+	backoff := time.Duration(t.policy.InitialBackoffSeconds) * time.Second
+	max := time.Duration(t.policy.MaxBackoffSeconds) * time.Second
+	for i := 1; i < consecutiveFailures && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
+
+// advance moves open circuit breakers whose cool-down elapsed at the given point in time to the half-open state.
+func (t *failureTracker) advance(now time.Time) {
+	for _, f := range t.failures {
+		if f.State == klyshkov1alpha1.CircuitBreakerOpen && (f.RetryTime == nil || !now.Before(f.RetryTime.Time)) {
+			f.State = klyshkov1alpha1.CircuitBreakerHalfOpen
+		}
+	}
+}
+
+// isBlocked checks whether jobs must not be scheduled for the given subject at the given point in time. This is the
+// case while the circuit breaker is open or the backoff did not elapse yet. For half-open circuit breakers, a single
+// trial job is allowed, i.e., scheduling is blocked as long as any of the given active jobs is for the subject.
+func (t *failureTracker) isBlocked(key failureKey, activeJobs []klyshkov1alpha1.TupleGenerationJob, now time.Time) bool {
+	f, exists := t.failures[key]
+	if !exists {
+		return false
+	}
+	switch f.State {
+	case klyshkov1alpha1.CircuitBreakerOpen:
+		return true
+	case klyshkov1alpha1.CircuitBreakerHalfOpen:
+		for _, job := range activeJobs {
+			if job.Spec.Type == key.tupleType && (key.generator == "" || job.Spec.Generator == key.generator) {
+				return true
+			}
+		}
+		return false
+	default:
+		return f.RetryTime != nil && now.Before(f.RetryTime.Time)
+	}
+}
+
+// status returns the failure states tracked ordered by tuple type and generator.
+func (t *failureTracker) status() []klyshkov1alpha1.FailureStatus {
+	var failures []klyshkov1alpha1.FailureStatus
+	for _, f := range t.failures {
+		failures = append(failures, *f)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Type != failures[j].Type {
+			return failures[i].Type < failures[j].Type
+		}
+		return failures[i].Generator < failures[j].Generator
+	})
+	return failures
+}
+
+// filter removes the policies for tuple types that are blocked at the given point in time. In addition, blocked
+// generators are removed from the candidates for the respective tuple type given in the map of candidates. Policies
+// for which no selectable candidate remains are removed as well.
+func (t *failureTracker) filter(policies []klyshkov1alpha1.TupleTypePolicy, candidatesByTupleType map[string][]candidateGenerator,
+	activeJobs []klyshkov1alpha1.TupleGenerationJob, now time.Time) []klyshkov1alpha1.TupleTypePolicy {
+	var filtered []klyshkov1alpha1.TupleTypePolicy
+	for _, policy := range policies {
+		if t.isBlocked(failureKey{tupleType: policy.Type}, activeJobs, now) {
+			continue
+		}
+		var candidates []candidateGenerator
+		for _, c := range candidatesByTupleType[policy.Type] {
+			if !t.isBlocked(failureKey{tupleType: policy.Type, generator: c.generator.Name}, activeJobs, now) {
+				candidates = append(candidates, c)
+			}
+		}
+		candidatesByTupleType[policy.Type] = candidates
+		if selectGenerator(policy.Type, policy.GeneratorSelection, candidates, nil) == nil {
+			continue
+		}
+		filtered = append(filtered, policy)
+	}
+	return filtered
+}
@@ -0,0 +1,202 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"strconv"
+	"time"
+)
+
+var _ = Describe("Tracking failures", func() {
+	const TupleType = "A"
+	const Generator = "g"
+	policy := klyshkov1alpha1.FailurePolicySpec{
+		InitialBackoffSeconds: 10,
+		MaxBackoffSeconds:     30,
+		FailureThreshold:      3,
+		CoolDownSeconds:       600,
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	typeKey := failureKey{tupleType: TupleType}
+	generatorKey := failureKey{tupleType: TupleType, generator: Generator}
+
+	var tracker *failureTracker
+	var observed []klyshkov1alpha1.ObservedJob
+	var jobs []klyshkov1alpha1.TupleGenerationJob
+	finishNamed := func(name string, state klyshkov1alpha1.TupleGenerationJobState, at time.Time) {
+		job := finishedJob(TupleType, Generator, state, 0)
+		job.Name = name
+		job.UID = types.UID(name)
+		job.Status.LastStateTransitionTime = metav1.NewTime(at)
+		jobs = append(jobs, job)
+		observed = tracker.observe(jobs, observed)
+	}
+	finish := func(state klyshkov1alpha1.TupleGenerationJobState, at time.Time) {
+		finishNamed("job-"+strconv.Itoa(len(jobs)), state, at)
+	}
+
+	BeforeEach(func() {
+		tracker = newFailureTracker(policy, nil)
+		observed = nil
+		jobs = nil
+	})
+
+	It("backs off exponentially after failures", func() {
+		finish(klyshkov1alpha1.JobFailed, start)
+		Expect(tracker.isBlocked(typeKey, nil, start.Add(9*time.Second))).To(BeTrue())
+		Expect(tracker.isBlocked(generatorKey, nil, start.Add(9*time.Second))).To(BeTrue())
+		Expect(tracker.isBlocked(typeKey, nil, start.Add(10*time.Second))).To(BeFalse())
+		finish(klyshkov1alpha1.JobFailed, start.Add(time.Minute))
+		Expect(tracker.isBlocked(typeKey, nil, start.Add(time.Minute+19*time.Second))).To(BeTrue())
+		Expect(tracker.isBlocked(typeKey, nil, start.Add(time.Minute+20*time.Second))).To(BeFalse())
+	})
+
+	It("bounds the backoff", func() {
+		Expect(tracker.backoff(10)).To(Equal(30 * time.Second))
+	})
+
+	It("observes each finished job only once", func() {
+		finish(klyshkov1alpha1.JobFailed, start)
+		tracker.observe(jobs, observed)
+		Expect(tracker.status()[0].ConsecutiveFailures).To(Equal(1))
+	})
+
+	It("observes jobs finishing at the same time as observed jobs regardless of their names", func() {
+		finishNamed("b", klyshkov1alpha1.JobFailed, start)
+		finishNamed("a", klyshkov1alpha1.JobFailed, start)
+		Expect(tracker.status()[0].ConsecutiveFailures).To(Equal(2))
+		Expect(observed).To(HaveLen(2))
+	})
+
+	It("forgets observed jobs that have been deleted", func() {
+		finish(klyshkov1alpha1.JobFailed, start)
+		finish(klyshkov1alpha1.JobFailed, start.Add(time.Second))
+		observed = tracker.observe(jobs[1:], observed)
+		Expect(observed).To(HaveLen(1))
+		Expect(observed[0].Name).To(Equal("job-1"))
+		Expect(tracker.status()[0].ConsecutiveFailures).To(Equal(2))
+	})
+
+	It("resets after a successful job", func() {
+		finish(klyshkov1alpha1.JobFailed, start)
+		finish(klyshkov1alpha1.JobCompleted, start.Add(time.Second))
+		Expect(tracker.status()).To(BeEmpty())
+		Expect(tracker.isBlocked(typeKey, nil, start.Add(2*time.Second))).To(BeFalse())
+	})
+
+	It("ignores cancelled jobs", func() {
+		finish(klyshkov1alpha1.JobFailed, start)
+		finish(klyshkov1alpha1.JobCancelled, start.Add(time.Second))
+		Expect(tracker.status()[0].ConsecutiveFailures).To(Equal(1))
+		Expect(observed).To(HaveLen(2))
+		Expect(tracker.isBlocked(typeKey, nil, start.Add(2*time.Second))).To(BeTrue())
+	})
+
+	When("the failure threshold is reached", func() {
+		BeforeEach(func() {
+			for i := 0; i < 3; i++ {
+				finish(klyshkov1alpha1.JobFailed, start.Add(time.Duration(i)*time.Minute))
+			}
+		})
+
+		It("opens the circuit breaker", func() {
+			Expect(tracker.status()[0].State).To(Equal(klyshkov1alpha1.CircuitBreakerOpen))
+			tracker.advance(start.Add(5 * time.Minute))
+			Expect(tracker.isBlocked(typeKey, nil, start.Add(5*time.Minute))).To(BeTrue())
+		})
+
+		It("half-opens the circuit breaker after the cool-down and allows a single trial job", func() {
+			now := start.Add(2*time.Minute + 600*time.Second)
+			tracker.advance(now)
+			Expect(tracker.status()[0].State).To(Equal(klyshkov1alpha1.CircuitBreakerHalfOpen))
+			Expect(tracker.isBlocked(typeKey, nil, now)).To(BeFalse())
+			trial := klyshkov1alpha1.TupleGenerationJob{
+				Spec: klyshkov1alpha1.TupleGenerationJobSpec{Type: TupleType, Generator: Generator},
+			}
+			Expect(tracker.isBlocked(typeKey, []klyshkov1alpha1.TupleGenerationJob{trial}, now)).To(BeTrue())
+		})
+
+		It("opens the circuit breaker again in case the trial job fails", func() {
+			now := start.Add(2*time.Minute + 600*time.Second)
+			tracker.advance(now)
+			finish(klyshkov1alpha1.JobFailed, now.Add(time.Minute))
+			Expect(tracker.status()[0].State).To(Equal(klyshkov1alpha1.CircuitBreakerOpen))
+		})
+
+		It("closes the circuit breaker in case the trial job succeeds", func() {
+			now := start.Add(2*time.Minute + 600*time.Second)
+			tracker.advance(now)
+			finish(klyshkov1alpha1.JobCompleted, now.Add(time.Minute))
+			Expect(tracker.status()).To(BeEmpty())
+		})
+	})
+
+	It("removes blocked generators from the candidates", func() {
+		other := generator("other", nil)
+		candidates := map[string][]candidateGenerator{
+			TupleType: {{generator: generator(Generator, nil), weight: 1}, {generator: other, weight: 1}},
+		}
+		tracker = newFailureTracker(policy, []klyshkov1alpha1.FailureStatus{{
+			Type:      TupleType,
+			Generator: Generator,
+			State:     klyshkov1alpha1.CircuitBreakerOpen,
+		}})
+		policies := tracker.filter([]klyshkov1alpha1.TupleTypePolicy{{Type: TupleType}}, candidates, nil, start)
+		Expect(policies).To(HaveLen(1))
+		Expect(candidates[TupleType]).To(HaveLen(1))
+		Expect(candidates[TupleType][0].generator.Name).To(Equal("other"))
+	})
+	It("observes failed jobs before removing them after the TTL", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.Suspend = true
+		scheduler.Spec.TTLSecondsAfterFinished = 1
+		scheduler.Spec.FailurePolicy = policy
+		job := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobFailed)
+		job.Status.LastStateTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+		r := newFakeSchedulerReconciler(nil, scheduler, job)
+
+		updated := reconcileScheduler(r, scheduler)
+		Expect(updated.Status.ObservedJobs).To(ConsistOf(HaveField("Name", "job")))
+		Expect(updated.Status.Failures).To(ContainElement(HaveField("ConsecutiveFailures", 1)))
+		jobs := &klyshkov1alpha1.TupleGenerationJobList{}
+		Expect(r.List(context.Background(), jobs)).To(Succeed())
+		Expect(jobs.Items).To(BeEmpty())
+	})
+
+	It("retains finished jobs until their observation has been persisted", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.Suspend = true
+		scheduler.Spec.TTLSecondsAfterFinished = 1
+		scheduler.Spec.FailurePolicy = policy
+		job := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobFailed)
+		job.Status.LastStateTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+		r := newFakeSchedulerReconciler(nil, scheduler, job)
+		c := r.Client
+		key := types.NamespacedName{Namespace: scheduler.Namespace, Name: scheduler.Name}
+
+		r.Client = failingStatusClient{c}
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		jobs := &klyshkov1alpha1.TupleGenerationJobList{}
+		Expect(c.List(context.Background(), jobs)).To(Succeed())
+		Expect(jobs.Items).To(HaveLen(1))
+
+		r.Client = c
+		updated := reconcileScheduler(r, scheduler)
+		Expect(updated.Status.Failures).To(ContainElement(HaveField("ConsecutiveFailures", 1)))
+		Expect(c.List(context.Background(), jobs)).To(Succeed())
+		Expect(jobs.Items).To(BeEmpty())
+	})
+})
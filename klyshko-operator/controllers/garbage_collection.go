@@ -0,0 +1,326 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/logging"
+	"github.com/go-logr/logr"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of orphans removed by the GarbageCollector.
+const (
+	orphanedRosterKey    = "RosterKey"
+	orphanedHeadRevision = "HeadRevision"
+	orphanedPVC          = "PersistentVolumeClaim"
+	orphanedService      = "Service"
+	orphanedPod          = "Pod"
+)
+
+// headsNamespace is the namespace whose VCP configuration applies to the head revisions of the players.
+const headsNamespace = "default"
+
+// orphan is an etcd key or a resource left behind by a job or task that no longer exists.
+type orphan struct {
+
+	// Kind is the kind of the orphan.
+	Kind string
+
+	// Name is the etcd key or the namespaced name of the resource.
+	Name string
+
+	// Object is the resource, or nil in case the orphan is an etcd key.
+	Object client.Object
+}
+
+// id returns the identifier used to track the given orphan across sweeps.
+func (o orphan) id() string {
+	return o.Kind + ":" + o.Name
+}
+
+// GarbageCollector periodically removes roster keys, head revisions, PVCs, services, and pods left behind by jobs and
+// tasks that no longer exist, e.g., because they have been deleted while the operator was down. Orphans are removed
+// only after they have been found to be orphaned for the grace period, such that resources created right before the
+// job or task they belong to are not collected. In dry-run mode, orphans are reported but not removed.
+type GarbageCollector struct {
+	client.Client
+	EtcdClient  *clientv3.Client
+	Logger      logr.Logger
+	Interval    time.Duration
+	GracePeriod time.Duration
+	DryRun      bool
+
+	// orphanedSince records when each of the orphans found by the last sweep has been found first.
+	orphanedSince map[string]time.Time
+}
+
+// NewGarbageCollector creates a GarbageCollector sweeping every interval.
+func NewGarbageCollector(client client.Client, etcdClient *clientv3.Client, logger logr.Logger, interval time.Duration, gracePeriod time.Duration, dryRun bool) *GarbageCollector {
+	return &GarbageCollector{
+		Client:        client,
+		EtcdClient:    etcdClient,
+		Logger:        logger,
+		Interval:      interval,
+		GracePeriod:   gracePeriod,
+		DryRun:        dryRun,
+		orphanedSince: map[string]time.Time{},
+	}
+}
+
+// Start sweeps periodically until the given context is done.
+func (gc *GarbageCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(gc.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if err := gc.sweep(ctx, now); err != nil {
+				gc.Logger.Error(err, "Garbage collection failed")
+			}
+		}
+	}
+}
+
+// sweep finds the orphans and removes the ones that have been orphaned for the grace period.
+func (gc *GarbageCollector) sweep(ctx context.Context, now time.Time) error {
+	rosterKeys, err := gc.etcdKeys(ctx, rosterKey+"/")
+	if err != nil {
+		return err
+	}
+	orphans, err := gc.findRosterOrphans(ctx, rosterKeys)
+	if err != nil {
+		return err
+	}
+	headKeys, err := gc.etcdKeys(ctx, headsKey+"/")
+	if err != nil {
+		return err
+	}
+	heads, err := gc.findHeadOrphans(ctx, headKeys)
+	if err != nil {
+		return err
+	}
+	resources, err := gc.findResourceOrphans(ctx)
+	if err != nil {
+		return err
+	}
+	orphans = append(append(orphans, heads...), resources...)
+	gc.collect(ctx, orphans, now)
+	return nil
+}
+
+// etcdKeys returns the etcd keys starting with the given prefix.
+func (gc *GarbageCollector) etcdKeys(ctx context.Context, prefix string) ([]string, error) {
+	resp, err := gc.EtcdClient.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keys with prefix %v: %w", prefix, err)
+	}
+	keys := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		keys = append(keys, string(kv.Key))
+	}
+	return keys, nil
+}
+
+// findRosterOrphans returns the given roster keys that are orphaned. Job rosters are orphaned in case the job does
+// not exist on the coordinating VCP anymore, as it is the coordinator that removes them otherwise. Roster entries are
+// orphaned in case the local task does not exist anymore, whereas roster entries of remote tasks are left to the
+// respective VCP. Keys in namespaces without VCP configuration are skipped.
+func (gc *GarbageCollector) findRosterOrphans(ctx context.Context, keys []string) ([]orphan, error) {
+	playerIDs := map[string]*uint{}
+	playerIDOf := func(namespace string) *uint {
+		if id, ok := playerIDs[namespace]; ok {
+			return id
+		}
+		id, err := localPlayerID(ctx, &gc.Client, namespace)
+		if err != nil {
+			gc.Logger.V(logging.DEBUG).Info("Skipping roster keys of namespace without VCP configuration",
+				"Namespace", namespace, "Error", err)
+			playerIDs[namespace] = nil
+			return nil
+		}
+		playerIDs[namespace] = &id
+		return &id
+	}
+	var orphans []orphan
+	for _, k := range keys {
+		key, err := ParseKey(k)
+		if err != nil {
+			continue
+		}
+		var name types.NamespacedName
+		var obj client.Object
+		switch key := key.(type) {
+		case RosterKey:
+			if playerID := playerIDOf(key.Namespace); playerID == nil || *playerID != coordinatorPlayerID {
+				continue
+			}
+			name, obj = key.NamespacedName, &klyshkov1alpha1.TupleGenerationJob{}
+		case RosterEntryKey:
+			if playerID := playerIDOf(key.Namespace); playerID == nil || *playerID != key.PlayerID {
+				continue
+			}
+			name = types.NamespacedName{Namespace: key.Namespace, Name: taskName(key.Name, key.PlayerID)}
+			obj = &klyshkov1alpha1.TupleGenerationTask{}
+		}
+		exists, err := gc.exists(ctx, name, obj)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			orphans = append(orphans, orphan{Kind: orphanedRosterKey, Name: k})
+		}
+	}
+	return orphans, nil
+}
+
+// findHeadOrphans returns the given head revision keys that are orphaned, i.e., belong to players that don't take part
+// in the VCP anymore. Head revisions are garbage collected by the coordinating VCP only.
+func (gc *GarbageCollector) findHeadOrphans(ctx context.Context, keys []string) ([]orphan, error) {
+	playerID, playerCount, err := parseVCPConfig(ctx, &gc.Client, headsNamespace)
+	if err != nil {
+		gc.Logger.V(logging.DEBUG).Info("Skipping head revisions without VCP configuration", "Error", err)
+		return nil, nil
+	}
+	if playerID != coordinatorPlayerID {
+		return nil, nil
+	}
+	var orphans []orphan
+	for _, k := range keys {
+		id, err := strconv.Atoi(strings.TrimPrefix(k, headsKey+"/"))
+		if err != nil || id < 0 || uint(id) < playerCount {
+			continue
+		}
+		orphans = append(orphans, orphan{Kind: orphanedHeadRevision, Name: k})
+	}
+	return orphans, nil
+}
+
+// findResourceOrphans returns the PVCs, services, and pods created for tasks that don't exist anymore as identified by
+// their OwnerTaskLabel.
+func (gc *GarbageCollector) findResourceOrphans(ctx context.Context) ([]orphan, error) {
+	var candidates []orphan
+	pvcs := &v1.PersistentVolumeClaimList{}
+	if err := gc.List(ctx, pvcs, client.HasLabels{OwnerTaskLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+	for i := range pvcs.Items {
+		candidates = append(candidates, orphan{Kind: orphanedPVC, Object: &pvcs.Items[i]})
+	}
+	services := &v1.ServiceList{}
+	if err := gc.List(ctx, services, client.HasLabels{OwnerTaskLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	for i := range services.Items {
+		candidates = append(candidates, orphan{Kind: orphanedService, Object: &services.Items[i]})
+	}
+	pods := &v1.PodList{}
+	if err := gc.List(ctx, pods, client.HasLabels{OwnerTaskLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for i := range pods.Items {
+		candidates = append(candidates, orphan{Kind: orphanedPod, Object: &pods.Items[i]})
+	}
+
+	var orphans []orphan
+	for _, c := range candidates {
+		task := types.NamespacedName{Namespace: c.Object.GetNamespace(), Name: c.Object.GetLabels()[OwnerTaskLabel]}
+		exists, err := gc.exists(ctx, task, &klyshkov1alpha1.TupleGenerationTask{})
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			c.Name = types.NamespacedName{Namespace: c.Object.GetNamespace(), Name: c.Object.GetName()}.String()
+			orphans = append(orphans, c)
+		}
+	}
+	return orphans, nil
+}
+
+// exists checks whether the resource with the given name exists by reading it into the given object.
+func (gc *GarbageCollector) exists(ctx context.Context, name types.NamespacedName, obj client.Object) (bool, error) {
+	if err := gc.Get(ctx, name, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read resource %v: %w", name, err)
+	}
+	return true, nil
+}
+
+// collect removes the given orphans that have been orphaned for the grace period as of the given point in time, or
+// reports them only in dry-run mode. Returns the orphans that have been removed or would have been removed.
+func (gc *GarbageCollector) collect(ctx context.Context, orphans []orphan, now time.Time) []orphan {
+	since := map[string]time.Time{}
+	found := map[string]int{}
+	var due []orphan
+	for _, o := range orphans {
+		found[o.Kind]++
+		first, ok := gc.orphanedSince[o.id()]
+		if !ok {
+			first = now
+		}
+		if now.Sub(first) < gc.GracePeriod {
+			since[o.id()] = first
+			continue
+		}
+		if gc.DryRun {
+			gc.Logger.Info("Orphan would be deleted (dry run)", "Kind", o.Kind, "Name", o.Name,
+				"OrphanedSince", first)
+			since[o.id()] = first
+			due = append(due, o)
+			continue
+		}
+		if err := gc.remove(ctx, o); err != nil {
+			gc.Logger.Error(err, "Failed to delete orphan", "Kind", o.Kind, "Name", o.Name)
+			since[o.id()] = first
+			continue
+		}
+		garbageCollectionDeletions.WithLabelValues(o.Kind).Inc()
+		gc.Logger.Info("Orphan deleted", "Kind", o.Kind, "Name", o.Name, "OrphanedSince", first)
+		due = append(due, o)
+	}
+	gc.orphanedSince = since
+	for _, kind := range []string{orphanedRosterKey, orphanedHeadRevision, orphanedPVC, orphanedService, orphanedPod} {
+		garbageCollectionOrphans.WithLabelValues(kind).Set(float64(found[kind]))
+	}
+	gc.Logger.V(logging.DEBUG).Info("Garbage collection sweep finished", "Orphans", found, "Collected", len(due),
+		"DryRun", gc.DryRun)
+	return due
+}
+
+// remove deletes the given orphan.
+func (gc *GarbageCollector) remove(ctx context.Context, o orphan) error {
+	if o.Object == nil {
+		if _, err := gc.EtcdClient.Delete(ctx, o.Name); err != nil {
+			return fmt.Errorf("failed to delete key %v: %w", o.Name, err)
+		}
+		return nil
+	}
+	if err := gc.Delete(ctx, o.Object); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %v %v: %w", o.Kind, o.Name, err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the garbage collector with the Manager, such that it runs on the leader only.
+func (gc *GarbageCollector) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(gc)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Describe("Garbage collection", func() {
+	task := func(name string) *klyshkov1alpha1.TupleGenerationTask {
+		return &klyshkov1alpha1.TupleGenerationTask{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+	labelled := func(obj client.Object, name string, taskName string) client.Object {
+		obj.SetName(name)
+		obj.SetNamespace("default")
+		obj.SetLabels(map[string]string{OwnerTaskLabel: taskName})
+		return obj
+	}
+	newCollector := func(playerID int, dryRun bool, objs ...client.Object) *GarbageCollector {
+		c := newFakeSchedulerReconcilerForPlayer(playerID, nil, objs...).Client
+		return NewGarbageCollector(c, nil, logr.Discard(), time.Minute, time.Hour, dryRun)
+	}
+	namesOf := func(orphans []orphan) []string {
+		var names []string
+		for _, o := range orphans {
+			names = append(names, o.Name)
+		}
+		return names
+	}
+
+	It("finds roster keys of jobs and local tasks that don't exist anymore", func() {
+		keys := []string{
+			"/klyshko/roster/default/job",
+			"/klyshko/roster/default/job/0",
+			"/klyshko/roster/default/job/1",
+			"/klyshko/roster/default/gone",
+			"/klyshko/roster/default/gone/0",
+			"/klyshko/roster/default/gone/1",
+			"/klyshko/roster/unconfigured/gone",
+		}
+		job := newTestJob(newTestScheduler("A"), "job", "A", klyshkov1alpha1.JobRunning)
+
+		gc := newCollector(coordinatorPlayerID, false, job, task("job-0"))
+		orphans, err := gc.findRosterOrphans(context.Background(), keys)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namesOf(orphans)).To(Equal([]string{"/klyshko/roster/default/gone", "/klyshko/roster/default/gone/0"}))
+
+		gc = newCollector(1, false, job, task("job-0"))
+		orphans, err = gc.findRosterOrphans(context.Background(), keys)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namesOf(orphans)).To(Equal([]string{"/klyshko/roster/default/job/1", "/klyshko/roster/default/gone/1"}))
+	})
+
+	It("finds head revisions of players not taking part in the VCP anymore on the coordinator", func() {
+		keys := []string{"/klyshko/heads/0", "/klyshko/heads/1", "/klyshko/heads/2"}
+		orphans, err := newCollector(coordinatorPlayerID, false).findHeadOrphans(context.Background(), keys)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namesOf(orphans)).To(Equal([]string{"/klyshko/heads/2"}))
+
+		orphans, err = newCollector(1, false).findHeadOrphans(context.Background(), keys)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orphans).To(BeEmpty())
+	})
+
+	It("finds PVCs, services, and pods of tasks that don't exist anymore", func() {
+		gc := newCollector(coordinatorPlayerID, false, task("job-0"),
+			labelled(&v1.PersistentVolumeClaim{}, "job-0", "job-0"),
+			labelled(&v1.PersistentVolumeClaim{}, "gone-0", "gone-0"),
+			labelled(&v1.Service{}, "gone-0", "gone-0"),
+			labelled(&v1.Pod{}, "gone-provisioner", "gone-0"),
+			&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default"}})
+		orphans, err := gc.findResourceOrphans(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namesOf(orphans)).To(Equal([]string{"default/gone-0", "default/gone-0", "default/gone-provisioner"}))
+		Expect([]string{orphans[0].Kind, orphans[1].Kind, orphans[2].Kind}).To(Equal(
+			[]string{orphanedPVC, orphanedService, orphanedPod}))
+	})
+
+	It("deletes orphans only after the grace period and reports them only in dry-run mode", func() {
+		now := time.Now()
+		for _, dryRun := range []bool{true, false} {
+			gc := newCollector(coordinatorPlayerID, dryRun, labelled(&v1.PersistentVolumeClaim{}, "gone-0", "gone-0"))
+			orphans, err := gc.findResourceOrphans(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gc.collect(context.Background(), orphans, now)).To(BeEmpty())
+			Expect(gc.collect(context.Background(), orphans, now.Add(30*time.Minute))).To(BeEmpty())
+			Expect(namesOf(gc.collect(context.Background(), orphans, now.Add(time.Hour)))).To(Equal([]string{"default/gone-0"}))
+
+			exists, err := gc.exists(context.Background(), types.NamespacedName{Namespace: "default", Name: "gone-0"},
+				&v1.PersistentVolumeClaim{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(Equal(dryRun))
+		}
+	})
+
+	It("restarts the grace period for orphans that have been resolved in between", func() {
+		now := time.Now()
+		gc := newCollector(coordinatorPlayerID, true)
+		key := []orphan{{Kind: orphanedRosterKey, Name: "/klyshko/roster/default/gone"}}
+		Expect(gc.collect(context.Background(), key, now)).To(BeEmpty())
+		Expect(gc.collect(context.Background(), nil, now.Add(30*time.Minute))).To(BeEmpty())
+		Expect(gc.collect(context.Background(), key, now.Add(time.Hour))).To(BeEmpty())
+		Expect(gc.collect(context.Background(), key, now.Add(2*time.Hour))).To(HaveLen(1))
+	})
+})
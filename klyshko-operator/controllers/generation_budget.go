@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
+	"time"
+)
+
+// DefaultBudgetWindow is the length of the rolling window the tuple quota of a budget applies to in case not given.
+const DefaultBudgetWindow = 24 * time.Hour
+
+// budgetTracker accounts the tuples and jobs generated per tuple type against the budgets of the tuple type policies.
+type budgetTracker struct {
+	budgets map[string]klyshkov1alpha1.GenerationBudget
+	history []klyshkov1alpha1.BudgetRecord
+}
+
+// newBudgetTracker creates a budget tracker for the budgets declared in the given policies initialized with the given
+// history of finished jobs.
+func newBudgetTracker(policies []klyshkov1alpha1.TupleTypePolicy, history []klyshkov1alpha1.BudgetRecord) *budgetTracker {
+	t := &budgetTracker{
+		budgets: map[string]klyshkov1alpha1.GenerationBudget{},
+	}
+	for _, policy := range policies {
+		if policy.Budget != nil {
+			t.budgets[policy.Type] = *policy.Budget
+		}
+	}
+	for _, r := range history {
+		t.history = append(t.history, *r.DeepCopy())
+	}
+	return t
+}
+
+// observe adds the finished jobs out of the given ones to the history in case their tuple type has a budget and they
+// are not recorded yet. Jobs are recorded with their creation time, or the given point in time if not available.
+// Records that no longer count towards any quota at the given point in time are dropped.
+func (t *budgetTracker) observe(jobs []klyshkov1alpha1.TupleGenerationJob, now time.Time) {
+	recorded := map[string]bool{}
+	for _, r := range t.history {
+		recorded[r.Job] = true
+	}
+	for _, job := range jobs {
+		if _, ok := t.budgets[job.Spec.Type]; !ok || !job.Status.State.IsDone() || recorded[job.Name] {
+			continue
+		}
+		created := job.CreationTimestamp
+		if created.IsZero() {
+			created = metav1.NewTime(now)
+		}
+		t.history = append(t.history, klyshkov1alpha1.BudgetRecord{
+			Job:   job.Name,
+			Type:  job.Spec.Type,
+			Count: job.Spec.Count,
+			Time:  created,
+		})
+	}
+	var retained []klyshkov1alpha1.BudgetRecord
+	for _, r := range t.history {
+		budget, ok := t.budgets[r.Type]
+		if !ok {
+			continue
+		}
+		retention := getBudgetWindow(budget)
+		if retention < time.Hour {
+			retention = time.Hour
+		}
+		if r.Time.Add(retention).After(now) {
+			retained = append(retained, r)
+		}
+	}
+	sort.SliceStable(retained, func(i, j int) bool {
+		return retained[i].Time.Before(&retained[j].Time)
+	})
+	t.history = retained
+}
+
+// usage returns the number of tuples generated within the rolling window of the budget and the number of jobs created
+// within the last hour for the given tuple type at the given point in time. The given active jobs always count towards
+// both quotas.
+func (t *budgetTracker) usage(tupleType string, activeJobs []klyshkov1alpha1.TupleGenerationJob, now time.Time) (tuples int, jobs int) {
+	budget := t.budgets[tupleType]
+	for _, r := range t.history {
+		if r.Type != tupleType {
+			continue
+		}
+		if r.Time.Add(getBudgetWindow(budget)).After(now) {
+			tuples += r.Count
+		}
+		if r.Time.Add(time.Hour).After(now) {
+			jobs++
+		}
+	}
+	for _, job := range activeJobs {
+		if job.Spec.Type == tupleType {
+			tuples += job.Spec.Count
+			jobs++
+		}
+	}
+	return tuples, jobs
+}
+
+// remainingTuples returns the number of tuples that can still be generated for the given tuple type at the given
+// point in time. In case the tuple type has no tuple quota, -1 is returned.
+func (t *budgetTracker) remainingTuples(tupleType string, activeJobs []klyshkov1alpha1.TupleGenerationJob, now time.Time) int {
+	budget, ok := t.budgets[tupleType]
+	if !ok || budget.MaxTuples <= 0 {
+		return -1
+	}
+	tuples, _ := t.usage(tupleType, activeJobs, now)
+	if tuples >= budget.MaxTuples {
+		return 0
+	}
+	return budget.MaxTuples - tuples
+}
+
+// isExhausted checks whether any of the quotas of the budget of the given tuple type is used up at the given point in
+// time.
+func (t *budgetTracker) isExhausted(tupleType string, activeJobs []klyshkov1alpha1.TupleGenerationJob, now time.Time) bool {
+	budget, ok := t.budgets[tupleType]
+	if !ok {
+		return false
+	}
+	tuples, jobs := t.usage(tupleType, activeJobs, now)
+	return (budget.MaxTuples > 0 && tuples >= budget.MaxTuples) ||
+		(budget.MaxJobsPerHour > 0 && jobs >= budget.MaxJobsPerHour)
+}
+
+// filter removes the policies for tuple types whose budget is exhausted at the given point in time and flags the
+// respective tuple type states. A budget is considered exhausted as well in case the remaining tuples don't suffice
+// for a single batch of any of the candidate generators of the tuple type.
+func (t *budgetTracker) filter(policies []klyshkov1alpha1.TupleTypePolicy, states []klyshkov1alpha1.TupleTypeStatus,
+	candidatesByTupleType map[string][]candidateGenerator, activeJobs []klyshkov1alpha1.TupleGenerationJob, now time.Time) []klyshkov1alpha1.TupleTypePolicy {
+	exhausted := func(tupleType string) bool {
+		if t.isExhausted(tupleType, activeJobs, now) {
+			return true
+		}
+		remaining := t.remainingTuples(tupleType, activeJobs, now)
+		if remaining < 0 {
+			return false
+		}
+		for _, c := range candidatesByTupleType[tupleType] {
+			if spec := c.generator.Spec.GetTupleTypeSpec(tupleType); spec != nil && spec.BatchSize <= remaining {
+				return false
+			}
+		}
+		return true
+	}
+	for idx := range states {
+		states[idx].BudgetExhausted = exhausted(states[idx].Type)
+	}
+	var filtered []klyshkov1alpha1.TupleTypePolicy
+	for _, policy := range policies {
+		if !exhausted(policy.Type) {
+			filtered = append(filtered, policy)
+		}
+	}
+	return filtered
+}
+
+// status returns the history of finished jobs ordered by creation time.
+func (t *budgetTracker) status() []klyshkov1alpha1.BudgetRecord {
+	return t.history
+}
+
+// getBudgetWindow returns the length of the rolling window of the given budget.
+func getBudgetWindow(budget klyshkov1alpha1.GenerationBudget) time.Duration {
+	if budget.Window.Duration <= 0 {
+		return DefaultBudgetWindow
+	}
+	return budget.Window.Duration
+}
+
+// limitJobSize shrinks the given job size to the given number of remaining tuples, rounded down to a multiple of the
+// given generator batch size. A remaining number of tuples of -1 denotes an unlimited budget. Returns 0 in case not even
+// a single batch fits into the budget.
+func limitJobSize(count int, batchSize int, remaining int) int {
+	if remaining < 0 || count <= remaining {
+		return count
+	}
+	return remaining / batchSize * batchSize
+}
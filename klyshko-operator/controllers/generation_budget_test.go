@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"strconv"
+	"time"
+)
+
+var _ = Describe("Enforcing budgets", func() {
+	const TupleType = "A"
+	const OtherTupleType = "B"
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	policies := []klyshkov1alpha1.TupleTypePolicy{
+		{Type: TupleType, Threshold: 1, Priority: 1, Budget: &klyshkov1alpha1.GenerationBudget{
+			MaxTuples:      3000,
+			Window:         metav1.Duration{Duration: 6 * time.Hour},
+			MaxJobsPerHour: 2,
+		}},
+		{Type: OtherTupleType, Threshold: 1, Priority: 1},
+	}
+
+	var tracker *budgetTracker
+	var jobs []klyshkov1alpha1.TupleGenerationJob
+	job := func(tupleType string, state klyshkov1alpha1.TupleGenerationJobState, created time.Time) klyshkov1alpha1.TupleGenerationJob {
+		j := finishedJob(tupleType, "g", state, 0)
+		j.Name = "job-" + strconv.Itoa(len(jobs))
+		j.CreationTimestamp = metav1.NewTime(created)
+		j.Spec.Count = 1000
+		jobs = append(jobs, j)
+		return j
+	}
+
+	BeforeEach(func() {
+		tracker = newBudgetTracker(policies, nil)
+		jobs = nil
+	})
+
+	It("records finished jobs for tuple types with a budget only once", func() {
+		job(TupleType, klyshkov1alpha1.JobCompleted, start)
+		job(TupleType, klyshkov1alpha1.JobFailed, start.Add(time.Minute))
+		job(TupleType, klyshkov1alpha1.JobRunning, start.Add(2*time.Minute))
+		job(OtherTupleType, klyshkov1alpha1.JobCompleted, start)
+		tracker.observe(jobs, start.Add(5*time.Minute))
+		tracker.observe(jobs, start.Add(6*time.Minute))
+		Expect(tracker.status()).To(Equal([]klyshkov1alpha1.BudgetRecord{
+			{Job: "job-0", Type: TupleType, Count: 1000, Time: metav1.NewTime(start)},
+			{Job: "job-1", Type: TupleType, Count: 1000, Time: metav1.NewTime(start.Add(time.Minute))},
+		}))
+	})
+
+	It("drops records that left the rolling window", func() {
+		job(TupleType, klyshkov1alpha1.JobCompleted, start)
+		tracker.observe(jobs, start.Add(time.Minute))
+		tracker.observe(nil, start.Add(6*time.Hour))
+		Expect(tracker.status()).To(BeEmpty())
+	})
+
+	It("accounts finished and active jobs against the quotas", func() {
+		job(TupleType, klyshkov1alpha1.JobCompleted, start)
+		tracker.observe(jobs, start.Add(time.Minute))
+		active := []klyshkov1alpha1.TupleGenerationJob{job(TupleType, klyshkov1alpha1.JobRunning, start.Add(2*time.Minute))}
+		tuples, jobsPerHour := tracker.usage(TupleType, active, start.Add(3*time.Minute))
+		Expect(tuples).To(Equal(2000))
+		Expect(jobsPerHour).To(Equal(2))
+		Expect(tracker.remainingTuples(TupleType, active, start.Add(3*time.Minute))).To(Equal(1000))
+		Expect(tracker.remainingTuples(OtherTupleType, active, start.Add(3*time.Minute))).To(Equal(-1))
+	})
+
+	It("exhausts the budget when the maximum number of jobs per hour has been created", func() {
+		job(TupleType, klyshkov1alpha1.JobCompleted, start)
+		job(TupleType, klyshkov1alpha1.JobCompleted, start.Add(10*time.Minute))
+		tracker.observe(jobs, start.Add(20*time.Minute))
+		Expect(tracker.isExhausted(TupleType, nil, start.Add(59*time.Minute))).To(BeTrue())
+		Expect(tracker.isExhausted(TupleType, nil, start.Add(time.Hour))).To(BeFalse())
+	})
+
+	It("exhausts the budget when the maximum number of tuples has been generated within the window", func() {
+		job(TupleType, klyshkov1alpha1.JobCompleted, start)
+		job(TupleType, klyshkov1alpha1.JobCompleted, start.Add(2*time.Hour))
+		job(TupleType, klyshkov1alpha1.JobCompleted, start.Add(4*time.Hour))
+		tracker.observe(jobs, start.Add(5*time.Hour))
+		Expect(tracker.isExhausted(TupleType, nil, start.Add(5*time.Hour))).To(BeTrue())
+		Expect(tracker.isExhausted(TupleType, nil, start.Add(6*time.Hour))).To(BeFalse())
+		Expect(tracker.isExhausted(OtherTupleType, nil, start.Add(5*time.Hour))).To(BeFalse())
+	})
+
+	It("removes policies whose remaining budget does not suffice for a single batch", func() {
+		job(TupleType, klyshkov1alpha1.JobCompleted, start)
+		job(TupleType, klyshkov1alpha1.JobCompleted, start.Add(2*time.Hour))
+		tracker.observe(jobs, start.Add(3*time.Hour))
+		g := generator("g", nil)
+		g.Spec.Supports = []klyshkov1alpha1.TupleTypeSpec{{Type: TupleType, BatchSize: 1500}}
+		candidates := map[string][]candidateGenerator{TupleType: {{generator: g, weight: 1}}}
+		states := []klyshkov1alpha1.TupleTypeStatus{{Type: TupleType}, {Type: OtherTupleType}}
+		filtered := tracker.filter(policies, states, candidates, nil, start.Add(3*time.Hour))
+		Expect(filtered).To(Equal(policies[1:]))
+		Expect(states[0].BudgetExhausted).To(BeTrue())
+		Expect(states[1].BudgetExhausted).To(BeFalse())
+
+		g.Spec.Supports[0].BatchSize = 1000
+		states[0].BudgetExhausted = false
+		Expect(tracker.filter(policies, states, candidates, nil, start.Add(3*time.Hour))).To(Equal(policies))
+		Expect(states[0].BudgetExhausted).To(BeFalse())
+	})
+
+	It("shrinks jobs to the remaining budget", func() {
+		Expect(limitJobSize(5000, 1000, -1)).To(Equal(5000))
+		Expect(limitJobSize(5000, 1000, 6000)).To(Equal(5000))
+		Expect(limitJobSize(5000, 1000, 3500)).To(Equal(3000))
+		Expect(limitJobSize(5000, 1000, 500)).To(Equal(0))
+	})
+	It("records finished jobs before removing them after the TTL", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.Suspend = true
+		scheduler.Spec.TTLSecondsAfterFinished = 1
+		scheduler.Spec.TupleTypePolicies = policies
+		finished := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobCompleted)
+		finished.Status.LastStateTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+		r := newFakeSchedulerReconciler(nil, scheduler, finished)
+
+		updated := reconcileScheduler(r, scheduler)
+		Expect(updated.Status.BudgetHistory).To(ConsistOf(HaveField("Job", "job")))
+		remaining := &klyshkov1alpha1.TupleGenerationJobList{}
+		Expect(r.List(context.Background(), remaining)).To(Succeed())
+		Expect(remaining.Items).To(BeEmpty())
+	})
+
+	It("retains finished jobs until their budget records have been persisted", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.Suspend = true
+		scheduler.Spec.TTLSecondsAfterFinished = 1
+		scheduler.Spec.TupleTypePolicies = policies
+		finished := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobCompleted)
+		finished.Status.LastStateTransitionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+		r := newFakeSchedulerReconciler(nil, scheduler, finished)
+		c := r.Client
+		key := types.NamespacedName{Namespace: scheduler.Namespace, Name: scheduler.Name}
+
+		r.Client = failingStatusClient{c}
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		remaining := &klyshkov1alpha1.TupleGenerationJobList{}
+		Expect(c.List(context.Background(), remaining)).To(Succeed())
+		Expect(remaining.Items).To(HaveLen(1))
+
+		r.Client = c
+		updated := reconcileScheduler(r, scheduler)
+		Expect(updated.Status.BudgetHistory).To(ConsistOf(HaveField("Job", "job")))
+		Expect(c.List(context.Background(), remaining)).To(Succeed())
+		Expect(remaining.Items).To(BeEmpty())
+	})
+})
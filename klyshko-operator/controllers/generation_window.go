@@ -0,0 +1,132 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/robfig/cron/v3"
+	"time"
+)
+
+const (
+	// NoWindows is the reason for the window of a scheduler being open because neither windows nor blackouts apply.
+	NoWindows = "NoWindows"
+
+	// InWindow is the reason for the window of a scheduler being open because one of its windows is active.
+	InWindow = "InWindow"
+
+	// OutsideWindows is the reason for the window of a scheduler being closed because none of its windows is active.
+	OutsideWindows = "OutsideWindows"
+
+	// InBlackout is the reason for the window of a scheduler being closed because one of its blackouts is active.
+	InBlackout = "InBlackout"
+
+	// InvalidWindow is the reason for the window of a scheduler being closed because a window or blackout or the time
+	// zone is malformed.
+	InvalidWindow = "InvalidWindow"
+)
+
+// windowState is the result of evaluating the windows and blackouts of a scheduler at a specific point in time.
+type windowState struct {
+	open    bool
+	reason  string
+	message string
+}
+
+// evaluateWindows determines whether tuples can be generated at the given point in time according to the windows and
+// blackouts declared in the given scheduler spec. Schedules are evaluated in the time zone given in the spec.
+func evaluateWindows(spec klyshkov1alpha1.TupleGenerationSchedulerSpec, now time.Time) (windowState, error) {
+	location, err := time.LoadLocation(spec.TimeZone)
+	if err != nil {
+		return windowState{}, fmt.Errorf("invalid time zone '%s': %w", spec.TimeZone, err)
+	}
+	now = now.In(location)
+
+	// Blackouts take precedence over windows
+	for _, blackout := range spec.Blackouts {
+		active, end, _, err := evaluateWindow(blackout, now)
+		if err != nil {
+			return windowState{}, err
+		}
+		if active {
+			return windowState{
+				open:    false,
+				reason:  InBlackout,
+				message: fmt.Sprintf("Blackout '%s' is active until %s", windowName(blackout), end.Format(time.RFC3339)),
+			}, nil
+		}
+	}
+	if len(spec.Windows) == 0 {
+		return windowState{open: true, reason: NoWindows, message: "No windows restrict tuple generation"}, nil
+	}
+
+	// Check whether any of the windows is active and keep track of the next one to start otherwise
+	var next time.Time
+	for _, window := range spec.Windows {
+		active, end, start, err := evaluateWindow(window, now)
+		if err != nil {
+			return windowState{}, err
+		}
+		if active {
+			return windowState{
+				open:    true,
+				reason:  InWindow,
+				message: fmt.Sprintf("Window '%s' is active until %s", windowName(window), end.Format(time.RFC3339)),
+			}, nil
+		}
+		if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	message := "No window is active"
+	if !next.IsZero() {
+		message = fmt.Sprintf("No window is active before %s", next.Format(time.RFC3339))
+	}
+	return windowState{open: false, reason: OutsideWindows, message: message}, nil
+}
+
+// evaluateWindow determines whether the given window is active at the given point in time. In case it is, the end of
+// the window is returned as well. Otherwise, the point in time the window starts next is returned.
+func evaluateWindow(window klyshkov1alpha1.TimeWindow, now time.Time) (active bool, end time.Time, next time.Time, err error) {
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return false, time.Time{}, time.Time{}, fmt.Errorf("invalid schedule '%s' of window '%s': %w",
+			window.Schedule, windowName(window), err)
+	}
+	duration := window.Duration.Duration
+	if duration <= 0 {
+		return false, time.Time{}, schedule.Next(now), nil
+	}
+
+	// The window is active iff it has been started within the last duration
+	start := schedule.Next(now.Add(-duration))
+	if start.IsZero() || start.After(now) {
+		return false, time.Time{}, start, nil
+	}
+	return true, start.Add(duration), time.Time{}, nil
+}
+
+// windowName returns the name of the given window or its schedule in case no name is given.
+func windowName(window klyshkov1alpha1.TimeWindow) string {
+	if window.Name != "" {
+		return window.Name
+	}
+	return window.Schedule
+}
+
+// IsWindowOpen reports whether tuples can be generated at the given point in time according to the windows and
+// blackouts declared in the given scheduler spec. An error is returned in case a window, blackout or the time zone is
+// malformed.
+func IsWindowOpen(spec klyshkov1alpha1.TupleGenerationSchedulerSpec, now time.Time) (bool, error) {
+	state, err := evaluateWindows(spec, now)
+	if err != nil {
+		return false, err
+	}
+	return state.open, nil
+}
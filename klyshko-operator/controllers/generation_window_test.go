@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+var _ = Describe("Evaluating generation windows", func() {
+	nightly := klyshkov1alpha1.TimeWindow{
+		Name:     "nightly",
+		Schedule: "0 22 * * *",
+		Duration: metav1.Duration{Duration: 8 * time.Hour},
+	}
+	maintenance := klyshkov1alpha1.TimeWindow{
+		Name:     "maintenance",
+		Schedule: "0 2 * * SUN",
+		Duration: metav1.Duration{Duration: time.Hour},
+	}
+	at := func(value string) time.Time {
+		t, err := time.Parse(time.RFC3339, value)
+		Expect(err).NotTo(HaveOccurred())
+		return t
+	}
+
+	When("neither windows nor blackouts are declared", func() {
+		It("is open", func() {
+			state, err := evaluateWindows(klyshkov1alpha1.TupleGenerationSchedulerSpec{}, time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.open).To(BeTrue())
+			Expect(state.reason).To(Equal(NoWindows))
+		})
+	})
+
+	When("windows are declared", func() {
+		spec := klyshkov1alpha1.TupleGenerationSchedulerSpec{Windows: []klyshkov1alpha1.TimeWindow{nightly}}
+		It("is open within a window", func() {
+			state, err := evaluateWindows(spec, at("2026-03-04T03:00:00Z"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.open).To(BeTrue())
+			Expect(state.reason).To(Equal(InWindow))
+		})
+		It("is closed outside of all windows", func() {
+			state, err := evaluateWindows(spec, at("2026-03-04T12:00:00Z"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.open).To(BeFalse())
+			Expect(state.reason).To(Equal(OutsideWindows))
+			Expect(state.message).To(ContainSubstring("2026-03-04T22:00:00Z"))
+		})
+		It("evaluates the schedule in the given time zone", func() {
+			berlin := spec
+			berlin.TimeZone = "Europe/Berlin"
+			// 21:30 UTC is 22:30 in Berlin during winter time
+			state, err := evaluateWindows(berlin, at("2026-03-04T21:30:00Z"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.open).To(BeTrue())
+			state, err = evaluateWindows(spec, at("2026-03-04T21:30:00Z"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.open).To(BeFalse())
+		})
+	})
+
+	When("a blackout is active", func() {
+		It("is closed even within a window", func() {
+			spec := klyshkov1alpha1.TupleGenerationSchedulerSpec{
+				Windows:   []klyshkov1alpha1.TimeWindow{nightly},
+				Blackouts: []klyshkov1alpha1.TimeWindow{maintenance},
+			}
+			// 2026-03-08 is a Sunday
+			state, err := evaluateWindows(spec, at("2026-03-08T02:30:00Z"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.open).To(BeFalse())
+			Expect(state.reason).To(Equal(InBlackout))
+		})
+	})
+
+	When("the specification is malformed", func() {
+		It("fails for an invalid schedule", func() {
+			_, err := evaluateWindows(klyshkov1alpha1.TupleGenerationSchedulerSpec{
+				Windows: []klyshkov1alpha1.TimeWindow{{Schedule: "every night"}},
+			}, time.Now())
+			Expect(err).To(HaveOccurred())
+		})
+		It("fails for an unknown time zone", func() {
+			_, err := evaluateWindows(klyshkov1alpha1.TupleGenerationSchedulerSpec{TimeZone: "Mars/Olympus"}, time.Now())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sort"
+)
+
+// candidateGenerator is a generator eligible to be used for a tuple type along with its weight.
+type candidateGenerator struct {
+	generator klyshkov1alpha1.TupleGenerator
+	weight    int
+}
+
+// getCandidateGenerators returns the generators out of the given ones supporting a tuple type that are candidates
+// according to the given generator selection. Candidates are returned in order of preference.
+func getCandidateGenerators(selection *klyshkov1alpha1.GeneratorSelectionSpec, generators []klyshkov1alpha1.TupleGenerator) ([]candidateGenerator, error) {
+	sorted := make([]klyshkov1alpha1.TupleGenerator, len(generators))
+	copy(sorted, generators)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	if selection == nil {
+		selection = &klyshkov1alpha1.GeneratorSelectionSpec{}
+	}
+
+	// Filter by label selector
+	selector := labels.Everything()
+	if selection.Selector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(selection.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid generator selector: %w", err)
+		}
+	}
+	var selected []klyshkov1alpha1.TupleGenerator
+	for _, generator := range sorted {
+		if selector.Matches(labels.Set(generator.Labels)) {
+			selected = append(selected, generator)
+		}
+	}
+
+	// All selected generators are equally weighted candidates in case no explicit list is given
+	var candidates []candidateGenerator
+	if len(selection.Generators) == 0 {
+		for _, generator := range selected {
+			candidates = append(candidates, candidateGenerator{generator: generator, weight: 1})
+		}
+		return candidates, nil
+	}
+
+	// Otherwise restrict to the listed generators in the order given
+	for _, preference := range selection.Generators {
+		for _, generator := range selected {
+			if generator.Name == preference.Name {
+				candidates = append(candidates, candidateGenerator{generator: generator, weight: preference.Weight})
+				break
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// selectGenerator selects the generator to be used for a job generating tuples of the given type out of the given
+// candidates according to the given generator selection. The given jobs are used to account for the share of jobs
+// already generated using a generator and to detect failing generators. Returns nil in case no candidate can be
+// selected.
+func selectGenerator(tupleType string, selection *klyshkov1alpha1.GeneratorSelectionSpec, candidates []candidateGenerator, jobs []klyshkov1alpha1.TupleGenerationJob) *klyshkov1alpha1.TupleGenerator {
+	policy := klyshkov1alpha1.WeightedGeneratorSelection
+	if selection != nil && selection.Policy != "" {
+		policy = selection.Policy
+	}
+	switch policy {
+	case klyshkov1alpha1.PreferredWithFallbackGeneratorSelection:
+		return selectPreferredGenerator(tupleType, candidates, jobs)
+	default:
+		return selectWeightedGenerator(tupleType, candidates, jobs)
+	}
+}
+
+// selectWeightedGenerator selects the candidate with the lowest ratio of jobs for the given tuple type to weight. This
+// results in a split of jobs among the candidates proportional to their weights. Ties are broken by preference.
+func selectWeightedGenerator(tupleType string, candidates []candidateGenerator, jobs []klyshkov1alpha1.TupleGenerationJob) *klyshkov1alpha1.TupleGenerator {
+	jobsByGenerator := map[string]int{}
+	for _, job := range jobs {
+		if job.Spec.Type == tupleType {
+			jobsByGenerator[job.Spec.Generator]++
+		}
+	}
+	var selected *candidateGenerator
+	for idx := range candidates {
+		candidate := &candidates[idx]
+		if candidate.weight <= 0 {
+			continue
+		}
+		// Compare jobs(c) / weight(c) < jobs(s) / weight(s) without resorting to floating point arithmetic
+		if selected == nil || jobsByGenerator[candidate.generator.Name]*selected.weight <
+			jobsByGenerator[selected.generator.Name]*candidate.weight {
+			selected = candidate
+		}
+	}
+	if selected == nil {
+		return nil
+	}
+	return &selected.generator
+}
+
+// selectPreferredGenerator selects the first candidate whose most recently finished job for the given tuple type did
+// not fail. In case all candidates are failing, the most preferred candidate is selected.
+func selectPreferredGenerator(tupleType string, candidates []candidateGenerator, jobs []klyshkov1alpha1.TupleGenerationJob) *klyshkov1alpha1.TupleGenerator {
+	if len(candidates) == 0 {
+		return nil
+	}
+	lastFinishedByGenerator := map[string]klyshkov1alpha1.TupleGenerationJob{}
+	for _, job := range jobs {
+		if job.Spec.Type != tupleType || !job.Status.State.IsDone() || job.Status.State == klyshkov1alpha1.JobCancelled {
+			continue
+		}
+		if last, exists := lastFinishedByGenerator[job.Spec.Generator]; !exists ||
+			last.Status.LastStateTransitionTime.Before(&job.Status.LastStateTransitionTime) {
+			lastFinishedByGenerator[job.Spec.Generator] = job
+		}
+	}
+	for idx := range candidates {
+		last, exists := lastFinishedByGenerator[candidates[idx].generator.Name]
+		if !exists || last.Status.State != klyshkov1alpha1.JobFailed {
+			return &candidates[idx].generator
+		}
+	}
+	return &candidates[0].generator
+}
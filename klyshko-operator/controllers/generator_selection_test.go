@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+func generator(name string, labels map[string]string) klyshkov1alpha1.TupleGenerator {
+	return klyshkov1alpha1.TupleGenerator{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func finishedJob(tupleType string, generator string, state klyshkov1alpha1.TupleGenerationJobState, age time.Duration) klyshkov1alpha1.TupleGenerationJob {
+	return klyshkov1alpha1.TupleGenerationJob{
+		Spec: klyshkov1alpha1.TupleGenerationJobSpec{Type: tupleType, Generator: generator},
+		Status: klyshkov1alpha1.TupleGenerationJobStatus{
+			State:                   state,
+			LastStateTransitionTime: metav1.NewTime(time.Now().Add(-age)),
+		},
+	}
+}
+
+var _ = Describe("Selecting generators", func() {
+	const TupleType = "A"
+	tee := generator("tee", map[string]string{"kind": "tee"})
+	fake := generator("fake", map[string]string{"kind": "fake"})
+	generators := []klyshkov1alpha1.TupleGenerator{tee, fake}
+
+	names := func(candidates []candidateGenerator) []string {
+		var names []string
+		for _, c := range candidates {
+			names = append(names, c.generator.Name)
+		}
+		return names
+	}
+
+	Describe("Determining candidates", func() {
+		It("orders generators by name if no list is given", func() {
+			candidates, err := getCandidateGenerators(nil, generators)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(candidates)).To(Equal([]string{"fake", "tee"}))
+		})
+		It("filters by label selector", func() {
+			candidates, err := getCandidateGenerators(&klyshkov1alpha1.GeneratorSelectionSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"kind": "tee"}},
+			}, generators)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(candidates)).To(Equal([]string{"tee"}))
+		})
+		It("restricts to listed generators in the given order", func() {
+			candidates, err := getCandidateGenerators(&klyshkov1alpha1.GeneratorSelectionSpec{
+				Generators: []klyshkov1alpha1.GeneratorPreference{{Name: "tee", Weight: 3}, {Name: "missing", Weight: 1}},
+			}, generators)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(candidates).To(HaveLen(1))
+			Expect(candidates[0].weight).To(Equal(3))
+		})
+		It("fails for a malformed selector", func() {
+			_, err := getCandidateGenerators(&klyshkov1alpha1.GeneratorSelectionSpec{
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "kind", Operator: "Unknown"},
+				}},
+			}, generators)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Using the weighted policy", func() {
+		selection := &klyshkov1alpha1.GeneratorSelectionSpec{
+			Policy: klyshkov1alpha1.WeightedGeneratorSelection,
+			Generators: []klyshkov1alpha1.GeneratorPreference{
+				{Name: "tee", Weight: 2},
+				{Name: "fake", Weight: 1},
+			},
+		}
+		It("splits jobs proportional to the weights", func() {
+			candidates, err := getCandidateGenerators(selection, generators)
+			Expect(err).NotTo(HaveOccurred())
+			var jobs []klyshkov1alpha1.TupleGenerationJob
+			var selected []string
+			for i := 0; i < 6; i++ {
+				g := selectGenerator(TupleType, selection, candidates, jobs)
+				Expect(g).NotTo(BeNil())
+				selected = append(selected, g.Name)
+				jobs = append(jobs, finishedJob(TupleType, g.Name, klyshkov1alpha1.JobRunning, 0))
+			}
+			Expect(selected).To(Equal([]string{"tee", "fake", "tee", "tee", "fake", "tee"}))
+		})
+		It("never selects generators with zero weight", func() {
+			candidates := []candidateGenerator{{generator: tee, weight: 0}}
+			Expect(selectGenerator(TupleType, selection, candidates, nil)).To(BeNil())
+		})
+	})
+
+	Describe("Using the preferred with fallback policy", func() {
+		selection := &klyshkov1alpha1.GeneratorSelectionSpec{
+			Policy: klyshkov1alpha1.PreferredWithFallbackGeneratorSelection,
+			Generators: []klyshkov1alpha1.GeneratorPreference{
+				{Name: "tee", Weight: 1},
+				{Name: "fake", Weight: 1},
+			},
+		}
+		var candidates []candidateGenerator
+		BeforeEach(func() {
+			var err error
+			candidates, err = getCandidateGenerators(selection, generators)
+			Expect(err).NotTo(HaveOccurred())
+		})
+		It("selects the preferred generator", func() {
+			Expect(selectGenerator(TupleType, selection, candidates, nil).Name).To(Equal("tee"))
+		})
+		It("falls back in case the last job of the preferred generator failed", func() {
+			jobs := []klyshkov1alpha1.TupleGenerationJob{
+				finishedJob(TupleType, "tee", klyshkov1alpha1.JobCompleted, time.Hour),
+				finishedJob(TupleType, "tee", klyshkov1alpha1.JobFailed, time.Minute),
+			}
+			Expect(selectGenerator(TupleType, selection, candidates, jobs).Name).To(Equal("fake"))
+		})
+		It("returns to the preferred generator once it succeeded again", func() {
+			jobs := []klyshkov1alpha1.TupleGenerationJob{
+				finishedJob(TupleType, "tee", klyshkov1alpha1.JobFailed, time.Hour),
+				finishedJob(TupleType, "tee", klyshkov1alpha1.JobCompleted, time.Minute),
+			}
+			Expect(selectGenerator(TupleType, selection, candidates, jobs).Name).To(Equal("tee"))
+		})
+		It("selects the preferred generator in case all are failing", func() {
+			jobs := []klyshkov1alpha1.TupleGenerationJob{
+				finishedJob(TupleType, "tee", klyshkov1alpha1.JobFailed, time.Minute),
+				finishedJob(TupleType, "fake", klyshkov1alpha1.JobFailed, time.Minute),
+			}
+			Expect(selectGenerator(TupleType, selection, candidates, jobs).Name).To(Equal("tee"))
+		})
+	})
+})
@@ -0,0 +1,142 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/logging"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+// admissionRetryPeriod defines the duration between two attempts to admit a queued job.
+const admissionRetryPeriod = 10 * time.Second
+
+// isStarted checks whether the given job has been admitted and is not done yet as indicated by its state. Sharded jobs
+// are never queued, as they don't spawn tasks of their own but are generated by their shards, which are queued
+// individually.
+func isStarted(job klyshkov1alpha1.TupleGenerationJob) bool {
+	return isSharded(job) || job.Status.State == klyshkov1alpha1.JobPending || job.Status.State == klyshkov1alpha1.JobRunning
+}
+
+// isAdmitted checks whether the given job may spawn tasks, i.e., whether it has been admitted as recorded in the given
+// roster or has been started already.
+func isAdmitted(job klyshkov1alpha1.TupleGenerationJob, roster rosterJob) bool {
+	return roster.Admitted || isStarted(job)
+}
+
+// queuedBefore checks whether job a is admitted before job b. Jobs with a higher priority are admitted first, jobs of
+// the same priority are admitted in the order they have been created.
+func queuedBefore(a klyshkov1alpha1.TupleGenerationJob, b klyshkov1alpha1.TupleGenerationJob) bool {
+	if a.Spec.Priority != b.Spec.Priority {
+		return a.Spec.Priority > b.Spec.Priority
+	}
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// queuePosition returns the zero-based position of the given job in the admission queue formed by the given jobs, i.e.,
+// the number of queued jobs to be admitted before the given one, and the number of jobs that are admitted and not done
+// yet.
+func queuePosition(jobs []klyshkov1alpha1.TupleGenerationJob, job klyshkov1alpha1.TupleGenerationJob) (int, int) {
+	position, active := 0, 0
+	for _, j := range jobs {
+		if j.Status.State.IsDone() || isSharded(j) || (j.Namespace == job.Namespace && j.Name == job.Name) {
+			continue
+		}
+		if isStarted(j) {
+			active++
+		} else if queuedBefore(j, job) {
+			position++
+		}
+	}
+	return position, active
+}
+
+// admitJob admits the given queued job in case it is at the head of the admission queue and the number of admitted
+// jobs that are not done yet is below the maximum number of concurrent jobs. The jobs are read from the API server
+// rather than the informer cache, as the cache may not reflect jobs admitted in preceding reconciliations yet. The
+// admission is recorded in the roster stored under the given key, such that the job is started on all VCPs. The roster
+// is updated only in case it has not been modified since the given revision. The job is started right away, such that
+// it counts as admitted when deciding on the admission of other jobs. To be invoked on the coordinating VCP only.
+func (r *TupleGenerationJobReconciler) admitJob(ctx context.Context, key RosterKey, revision int64, roster *rosterJob, job *klyshkov1alpha1.TupleGenerationJob) (bool, error) {
+	if r.MaxConcurrentJobs > 0 {
+		jobs := &klyshkov1alpha1.TupleGenerationJobList{}
+		if err := r.APIReader.List(ctx, jobs); err != nil {
+			return false, fmt.Errorf("failed to list jobs for admitting job %v: %w", job.Name, err)
+		}
+		position, active := queuePosition(jobs.Items, *job)
+		if active+position >= r.MaxConcurrentJobs {
+			r.Logger.V(logging.DEBUG).Info("Job queued", "Job.Key", key, "Position", position, "Active", active)
+			return false, nil
+		}
+	}
+	roster.Admitted = true
+	if err := r.updateRoster(ctx, key, revision, roster); err != nil {
+		return false, fmt.Errorf("failed to record admission in roster for job %v: %w", key.Name, err)
+	}
+	r.Logger.Info("Job admitted", "Job.Key", key, "Priority", job.Spec.Priority)
+	return true, r.startJob(ctx, job)
+}
+
+// queueJob transitions the given job into the queued state, unless it is queued already.
+func (r *TupleGenerationJobReconciler) queueJob(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob) error {
+	if job.Status.State == klyshkov1alpha1.JobQueued {
+		return nil
+	}
+	job.Status.State = klyshkov1alpha1.JobQueued
+	job.Status.LastStateTransitionTime = metav1.Now()
+	setFinishedCondition(job)
+	if err := r.Status().Update(ctx, job); err != nil {
+		return fmt.Errorf("status update failed for job %v: %w", job.Name, err)
+	}
+	return nil
+}
+
+// applyRosterAdmission starts the queued job with the given name in case its admission has been recorded in the given
+// roster by the coordinating VCP.
+func (r *TupleGenerationJobReconciler) applyRosterAdmission(ctx context.Context, name types.NamespacedName, roster rosterJob) error {
+	if !roster.Admitted || roster.hasVerdict() {
+		return nil
+	}
+	job := &klyshkov1alpha1.TupleGenerationJob{}
+	if err := r.Get(ctx, name, job); err != nil {
+		return fmt.Errorf("failed to read resource for job %v: %w", name.Name, err)
+	}
+	if job.Status.State.IsDone() {
+		return nil
+	}
+	return r.startJob(ctx, job)
+}
+
+// startJob transitions the given admitted job into the pending state and records the start time the deadline of the
+// job is measured from, unless it has been started already.
+func (r *TupleGenerationJobReconciler) startJob(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob) error {
+	if isStarted(*job) {
+		return nil
+	}
+	now := metav1.Now()
+	job.Status.State = klyshkov1alpha1.JobPending
+	job.Status.LastStateTransitionTime = now
+	if job.Status.StartTime == nil {
+		job.Status.StartTime = &now
+	}
+	setFinishedCondition(job)
+	if err := r.Status().Update(ctx, job); err != nil {
+		return fmt.Errorf("status update failed for job %v: %w", job.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+var _ = Describe("Admitting jobs", func() {
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	job := func(name string, priority int, age time.Duration, state klyshkov1alpha1.TupleGenerationJobState) *klyshkov1alpha1.TupleGenerationJob {
+		j := newTestJob(newTestScheduler("A"), name, "A", state)
+		j.Spec.Priority = priority
+		j.CreationTimestamp = metav1.NewTime(created.Add(-age))
+		return j
+	}
+	jobsOf := func(jobs ...*klyshkov1alpha1.TupleGenerationJob) []klyshkov1alpha1.TupleGenerationJob {
+		var items []klyshkov1alpha1.TupleGenerationJob
+		for _, j := range jobs {
+			items = append(items, *j)
+		}
+		return items
+	}
+
+	It("admits jobs in order of priority and age", func() {
+		Expect(queuedBefore(*job("a", 1, 0, ""), *job("b", 0, time.Minute, ""))).To(BeTrue())
+		Expect(queuedBefore(*job("a", 0, 0, ""), *job("b", 0, time.Minute, ""))).To(BeFalse())
+		Expect(queuedBefore(*job("a", 0, 0, ""), *job("b", 0, 0, ""))).To(BeTrue())
+	})
+
+	It("counts the admitted jobs that are not done and the queued jobs ahead", func() {
+		queued := job("queued", 0, 0, klyshkov1alpha1.JobQueued)
+		jobs := jobsOf(queued,
+			job("running", 0, 0, klyshkov1alpha1.JobRunning),
+			job("pending", -1, 0, klyshkov1alpha1.JobPending),
+			job("completed", 0, 0, klyshkov1alpha1.JobCompleted),
+			job("urgent", 5, 0, klyshkov1alpha1.JobQueued),
+			job("older", 0, time.Minute, ""),
+			job("later", 0, -time.Minute, klyshkov1alpha1.JobQueued),
+			job("low", -1, time.Hour, klyshkov1alpha1.JobQueued),
+		)
+		sharded := job("sharded", 0, 0, klyshkov1alpha1.JobRunning)
+		sharded.Spec.Sharding = &klyshkov1alpha1.JobShardingSpec{Shards: 2}
+		jobs = append(jobs, *sharded)
+
+		position, active := queuePosition(jobs, *queued)
+		Expect(position).To(Equal(2))
+		Expect(active).To(Equal(2))
+	})
+
+	It("keeps jobs queued while the maximum number of concurrent jobs is reached", func() {
+		queued := job("queued", 0, 0, "")
+		c := newFakeSchedulerReconciler(nil, queued, job("running", 0, 0, klyshkov1alpha1.JobRunning)).Client
+		r := &TupleGenerationJobReconciler{Client: c, APIReader: c, Logger: logr.Discard(), MaxConcurrentJobs: 1}
+		roster := rosterJob{TupleGenerationJobSpec: queued.Spec}
+		admitted, err := r.admitJob(context.Background(), RosterKey{types.NamespacedName{Namespace: "default", Name: "queued"}}, 0, &roster, queued)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(admitted).To(BeFalse())
+		Expect(roster.Admitted).To(BeFalse())
+		Expect(isAdmitted(*queued, roster)).To(BeFalse())
+
+		Expect(r.queueJob(context.Background(), queued)).To(Succeed())
+		updated := &klyshkov1alpha1.TupleGenerationJob{}
+		Expect(r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "queued"}, updated)).To(Succeed())
+		Expect(updated.Status.State).To(Equal(klyshkov1alpha1.JobQueued))
+	})
+
+	It("counts admitted jobs not yet reflected by the informer cache", func() {
+		queued := job("queued", 0, 0, "")
+		c := newFakeSchedulerReconciler(nil, queued).Client
+		live := newFakeSchedulerReconciler(nil, queued, job("admitted", 0, 0, klyshkov1alpha1.JobPending)).Client
+		r := &TupleGenerationJobReconciler{Client: c, APIReader: live, Logger: logr.Discard(), MaxConcurrentJobs: 1}
+		roster := rosterJob{TupleGenerationJobSpec: queued.Spec}
+		admitted, err := r.admitJob(context.Background(), RosterKey{types.NamespacedName{Namespace: "default", Name: "queued"}}, 0, &roster, queued)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(admitted).To(BeFalse())
+	})
+
+	It("starts queued jobs on VCPs other than the coordinator as soon as the roster admits them", func() {
+		queued := job("queued", 0, 0, klyshkov1alpha1.JobQueued)
+		c := newFakeSchedulerReconciler(nil, queued).Client
+		r := &TupleGenerationJobReconciler{Client: c, Logger: logr.Discard()}
+		name := types.NamespacedName{Namespace: "default", Name: "queued"}
+
+		Expect(r.applyRosterAdmission(context.Background(), name, rosterJob{})).To(Succeed())
+		updated := &klyshkov1alpha1.TupleGenerationJob{}
+		Expect(r.Get(context.Background(), name, updated)).To(Succeed())
+		Expect(updated.Status.State).To(Equal(klyshkov1alpha1.JobQueued))
+		Expect(updated.Status.StartTime).To(BeNil())
+
+		Expect(r.applyRosterAdmission(context.Background(), name, rosterJob{Admitted: true})).To(Succeed())
+		Expect(r.Get(context.Background(), name, updated)).To(Succeed())
+		Expect(updated.Status.State).To(Equal(klyshkov1alpha1.JobPending))
+		Expect(updated.Status.StartTime).NotTo(BeNil())
+		Expect(isStarted(*updated)).To(BeTrue())
+	})
+})
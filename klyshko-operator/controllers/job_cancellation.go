@@ -0,0 +1,51 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// cancelJobInRoster records that the job with the given key has been cancelled in its roster, such that all VCPs
+// cancel the job consistently. The roster is updated only in case it has not been modified since the given revision.
+// Can be invoked on any VCP.
+func (r *TupleGenerationJobReconciler) cancelJobInRoster(ctx context.Context, key RosterKey, revision int64, roster *rosterJob) error {
+	roster.Cancelled = true
+	if err := r.updateRoster(ctx, key, revision, roster); err != nil {
+		return fmt.Errorf("failed to record cancellation in roster for job %v: %w", key.Name, err)
+	}
+	r.Logger.Info("Job cancelled", "Job.Key", key)
+	return nil
+}
+
+// cancelTask terminates the generator and provisioner pods of the task with the given key, if any, and transitions the
+// task into the cancelled state.
+func (r *TupleGenerationTaskReconciler) cancelTask(ctx context.Context, taskKey RosterEntryKey, task *klyshkov1alpha1.TupleGenerationTask, status *klyshkov1alpha1.TupleGenerationTaskStatus) error {
+	pods := []types.NamespacedName{
+		{Namespace: task.Namespace, Name: task.Name},
+		{Namespace: taskKey.Namespace, Name: r.provisionerPodName(taskKey)},
+	}
+	for _, name := range pods {
+		pod := &v1.Pod{}
+		if err := r.Get(ctx, name, pod); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to read pod %v of task %v: %w", name.Name, task.Name, err)
+		}
+		if err := r.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod %v of task %v: %w", name.Name, task.Name, err)
+		}
+	}
+	return r.setState(ctx, taskKey, status, klyshkov1alpha1.TaskCancelled)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+var _ = Describe("Cancelling jobs", func() {
+	It("gives cancellation precedence over failure", func() {
+		roster := rosterJob{}
+		Expect(roster.hasVerdict()).To(BeFalse())
+
+		roster.Failure = &jobFailure{Reason: klyshkov1alpha1.JobDeadlineExceeded, Message: "too slow"}
+		Expect(roster.hasVerdict()).To(BeTrue())
+		state, reason, _ := roster.verdict()
+		Expect(state).To(Equal(klyshkov1alpha1.JobFailed))
+		Expect(reason).To(Equal(klyshkov1alpha1.JobDeadlineExceeded))
+
+		roster.Cancelled = true
+		state, reason, _ = roster.verdict()
+		Expect(state).To(Equal(klyshkov1alpha1.JobCancelled))
+		Expect(reason).To(Equal(klyshkov1alpha1.JobCancellationRequested))
+	})
+
+	It("stores the cancellation in the roster", func() {
+		encoded, err := json.Marshal(rosterJob{Cancelled: true})
+		Expect(err).NotTo(HaveOccurred())
+		roster := rosterJob{}
+		Expect(json.Unmarshal(encoded, &roster)).To(Succeed())
+		Expect(roster.Cancelled).To(BeTrue())
+	})
+
+	It("considers cancelled jobs and tasks to be done", func() {
+		Expect(klyshkov1alpha1.JobCancelled.IsValid()).To(BeTrue())
+		Expect(klyshkov1alpha1.JobCancelled.IsDone()).To(BeTrue())
+		Expect(klyshkov1alpha1.TaskCancelled.IsValid()).To(BeTrue())
+		Expect(klyshkov1alpha1.TaskCancelled.IsDone()).To(BeTrue())
+	})
+
+	It("does not retry failed jobs that have been cancelled", func() {
+		job := finishedJob("A", "generator", klyshkov1alpha1.JobFailed, 0)
+		job.Spec.RetryPolicy = &klyshkov1alpha1.JobRetryPolicy{MaxRetries: 1}
+		Expect(isRetryPending(job)).To(BeTrue())
+		job.Spec.Cancel = true
+		Expect(isRetryPending(job)).To(BeFalse())
+	})
+
+	It("applies the cancellation to jobs that are not done yet", func() {
+		scheduler := newTestScheduler("A")
+		running := newTestJob(scheduler, "running", "A", klyshkov1alpha1.JobRunning)
+		r := &TupleGenerationJobReconciler{Client: newFakeSchedulerReconciler(nil, running).Client}
+		name := types.NamespacedName{Namespace: "default", Name: "running"}
+
+		Expect(r.applyRosterVerdict(context.Background(), name, rosterJob{Cancelled: true})).To(Succeed())
+		job := &klyshkov1alpha1.TupleGenerationJob{}
+		Expect(r.Get(context.Background(), name, job)).To(Succeed())
+		Expect(job.Status.State).To(Equal(klyshkov1alpha1.JobCancelled))
+		Expect(job.Status.Reason).To(Equal(klyshkov1alpha1.JobCancellationRequested))
+	})
+
+	It("does not take cancelled jobs into account when selecting generators", func() {
+		preferred := generator("preferred", nil)
+		fallback := generator("fallback", nil)
+		failed := finishedJob("A", "preferred", klyshkov1alpha1.JobFailed, 2*time.Minute)
+		cancelled := finishedJob("A", "preferred", klyshkov1alpha1.JobCancelled, time.Minute)
+		selected := selectPreferredGenerator("A", []candidateGenerator{{generator: preferred}, {generator: fallback}},
+			[]klyshkov1alpha1.TupleGenerationJob{failed, cancelled})
+		Expect(selected.Name).To(Equal("fallback"))
+	})
+})
@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+// jobFailure is the verdict of the coordinating VCP that a job failed.
+type jobFailure struct {
+
+	// Reason is a machine-readable CamelCase reason for the job having failed.
+	Reason string `json:"reason"`
+
+	// Message is a human-readable description of why the job failed.
+	Message string `json:"message,omitempty"`
+}
+
+// getJobDeadline returns the point in time the given job fails in case it is not done by then. The deadline is
+// measured from the start time of the job, such that time spent queued doesn't count. Returns false in case the job has
+// no deadline or has not been started yet.
+func getJobDeadline(job klyshkov1alpha1.TupleGenerationJob) (time.Time, bool) {
+	if job.Spec.ActiveDeadlineSeconds == nil || job.Status.StartTime == nil {
+		return time.Time{}, false
+	}
+	return job.Status.StartTime.Add(time.Duration(*job.Spec.ActiveDeadlineSeconds) * time.Second), true
+}
+
+// getTaskTimeout returns how long a task may stay in the given state according to the given timeouts. Returns zero in
+// case the time is not bounded.
+func getTaskTimeout(timeouts *klyshkov1alpha1.TaskTimeouts, state klyshkov1alpha1.TupleGenerationTaskState) time.Duration {
+	if timeouts == nil {
+		return 0
+	}
+	var seconds int
+	switch state {
+	case klyshkov1alpha1.TaskPreparing:
+		seconds = timeouts.PreparingSeconds
+	case klyshkov1alpha1.TaskLaunching:
+		seconds = timeouts.LaunchingSeconds
+	case klyshkov1alpha1.TaskGenerating:
+		seconds = timeouts.GeneratingSeconds
+	case klyshkov1alpha1.TaskProvisioning:
+		seconds = timeouts.ProvisioningSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// describeTaskFailures returns a description of why the given tasks failed.
+func describeTaskFailures(tasks []klyshkov1alpha1.TupleGenerationTask) string {
+	var failures []string
+	for _, t := range tasks {
+		if t.Status.State != klyshkov1alpha1.TaskFailed {
+			continue
+		}
+		failure := fmt.Sprintf("Task of player %d failed", t.Spec.PlayerID)
+		if t.Status.Reason != "" {
+			failure += fmt.Sprintf(" (%s)", t.Status.Reason)
+		}
+		if t.Status.Message != "" {
+			failure += ": " + t.Status.Message
+		}
+		failures = append(failures, failure)
+	}
+	sort.Strings(failures)
+	return strings.Join(failures, "; ")
+}
+
+// failJobInRoster records the verdict that the job with the given key failed for the given reason in its roster, such
+// that all VCPs fail the job consistently. The roster is updated only in case it has not been modified since the given
+// revision. To be invoked on the coordinating VCP only.
+func (r *TupleGenerationJobReconciler) failJobInRoster(ctx context.Context, key RosterKey, revision int64, roster *rosterJob, reason string, message string) error {
+	roster.Failure = &jobFailure{Reason: reason, Message: message}
+	if err := r.updateRoster(ctx, key, revision, roster); err != nil {
+		return fmt.Errorf("failed to record failure in roster for job %v: %w", key.Name, err)
+	}
+	r.Logger.Info("Job failed", "Job.Key", key, "Reason", reason, "Message", message)
+	return nil
+}
+
+// applyRosterVerdict cancels or fails the job with the given name according to the verdict recorded in the given roster,
+// if any, unless the job is done already or the verdict doesn't apply to it. Sharded jobs are not cancelled or failed
+// anymore as soon as all of their shards completed.
+func (r *TupleGenerationJobReconciler) applyRosterVerdict(ctx context.Context, name types.NamespacedName, roster rosterJob) error {
+	if !roster.hasVerdict() {
+		return nil
+	}
+	job := &klyshkov1alpha1.TupleGenerationJob{}
+	if err := r.Get(ctx, name, job); err != nil {
+		return fmt.Errorf("failed to read resource for job %v: %w", name.Name, err)
+	}
+	if job.Status.State.IsDone() || !appliesVerdict(*job, roster) {
+		return nil
+	}
+	if isSharded(*job) {
+		generated, err := r.tuplesGenerated(ctx, job)
+		if err != nil {
+			return err
+		}
+		if generated {
+			return nil
+		}
+	}
+	job.Status.State, job.Status.Reason, job.Status.Message = roster.verdict()
+	job.Status.LastStateTransitionTime = metav1.Now()
+	if err := r.Status().Update(ctx, job); err != nil {
+		return fmt.Errorf("status update failed for job %v: %w", name.Name, err)
+	}
+	return nil
+}
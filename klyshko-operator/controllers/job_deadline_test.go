@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+var _ = Describe("Job deadlines and task timeouts", func() {
+	failedTask := func(playerID uint, reason string, message string) klyshkov1alpha1.TupleGenerationTask {
+		return klyshkov1alpha1.TupleGenerationTask{
+			Spec: klyshkov1alpha1.TupleGenerationTaskSpec{PlayerID: playerID},
+			Status: klyshkov1alpha1.TupleGenerationTaskStatus{
+				State:   klyshkov1alpha1.TaskFailed,
+				Reason:  reason,
+				Message: message,
+			},
+		}
+	}
+
+	It("computes the deadline from the start time of the job", func() {
+		created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		job := klyshkov1alpha1.TupleGenerationJob{}
+		job.CreationTimestamp = metav1.NewTime(created)
+		_, ok := getJobDeadline(job)
+		Expect(ok).To(BeFalse())
+
+		deadline := int64(90)
+		job.Spec.ActiveDeadlineSeconds = &deadline
+		_, ok = getJobDeadline(job)
+		Expect(ok).To(BeFalse())
+
+		started := metav1.NewTime(created.Add(time.Hour))
+		job.Status.StartTime = &started
+		at, ok := getJobDeadline(job)
+		Expect(ok).To(BeTrue())
+		Expect(at).To(Equal(started.Add(90 * time.Second)))
+	})
+
+	It("bounds the time spent in the active task states only", func() {
+		timeouts := &klyshkov1alpha1.TaskTimeouts{PreparingSeconds: 60, GeneratingSeconds: 3600}
+		Expect(getTaskTimeout(timeouts, klyshkov1alpha1.TaskPreparing)).To(Equal(time.Minute))
+		Expect(getTaskTimeout(timeouts, klyshkov1alpha1.TaskGenerating)).To(Equal(time.Hour))
+		Expect(getTaskTimeout(timeouts, klyshkov1alpha1.TaskLaunching)).To(BeZero())
+		Expect(getTaskTimeout(timeouts, klyshkov1alpha1.TaskFailed)).To(BeZero())
+		Expect(getTaskTimeout(nil, klyshkov1alpha1.TaskPreparing)).To(BeZero())
+	})
+
+	It("considers failed and completed tasks to be done", func() {
+		Expect(klyshkov1alpha1.TaskFailed.IsDone()).To(BeTrue())
+		Expect(klyshkov1alpha1.TaskCompleted.IsDone()).To(BeTrue())
+		Expect(klyshkov1alpha1.TaskGenerating.IsDone()).To(BeFalse())
+	})
+
+	It("describes why the tasks of a job failed", func() {
+		tasks := []klyshkov1alpha1.TupleGenerationTask{
+			failedTask(1, klyshkov1alpha1.TaskTimedOut, "Task did not leave state Preparing within 1m0s"),
+			{Status: klyshkov1alpha1.TupleGenerationTaskStatus{State: klyshkov1alpha1.TaskCompleted}},
+			failedTask(0, klyshkov1alpha1.TaskPodFailed, "Generator pod failed"),
+			failedTask(2, "", ""),
+		}
+		Expect(describeTaskFailures(tasks)).To(Equal("Task of player 0 failed (PodFailed): Generator pod failed; " +
+			"Task of player 1 failed (TimedOut): Task did not leave state Preparing within 1m0s; " +
+			"Task of player 2 failed"))
+	})
+
+	It("stores the failure verdict in the roster", func() {
+		roster := rosterJob{
+			TupleGenerationJobSpec: klyshkov1alpha1.TupleGenerationJobSpec{ID: "id", Count: 1000},
+			Failure:                &jobFailure{Reason: klyshkov1alpha1.JobDeadlineExceeded, Message: "too slow"},
+		}
+		encoded, err := json.Marshal(roster)
+		Expect(err).NotTo(HaveOccurred())
+		decoded := rosterJob{}
+		Expect(json.Unmarshal(encoded, &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(roster))
+	})
+
+	It("applies the failure verdict to jobs that are not done yet", func() {
+		scheduler := newTestScheduler("A")
+		running := newTestJob(scheduler, "running", "A", klyshkov1alpha1.JobRunning)
+		completed := newTestJob(scheduler, "completed", "A", klyshkov1alpha1.JobCompleted)
+		r := &TupleGenerationJobReconciler{Client: newFakeSchedulerReconciler(nil, running, completed).Client}
+		failure := &jobFailure{Reason: klyshkov1alpha1.JobDeadlineExceeded, Message: "too slow"}
+
+		for _, name := range []string{"running", "completed"} {
+			Expect(r.applyRosterVerdict(context.Background(),
+				types.NamespacedName{Namespace: "default", Name: name}, rosterJob{Failure: failure})).To(Succeed())
+		}
+		job := &klyshkov1alpha1.TupleGenerationJob{}
+		Expect(r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "running"}, job)).To(Succeed())
+		Expect(job.Status.State).To(Equal(klyshkov1alpha1.JobFailed))
+		Expect(job.Status.Reason).To(Equal(klyshkov1alpha1.JobDeadlineExceeded))
+		Expect(job.Status.Message).To(Equal("too slow"))
+		job = &klyshkov1alpha1.TupleGenerationJob{}
+		Expect(r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "completed"}, job)).To(Succeed())
+		Expect(job.Status.State).To(Equal(klyshkov1alpha1.JobCompleted))
+		Expect(job.Status.Reason).To(BeEmpty())
+	})
+})
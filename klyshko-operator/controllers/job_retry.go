@@ -0,0 +1,221 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/logging"
+	"github.com/google/uuid"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// rosterJob is the data stored in the roster of a job. In addition to the specification of the job, it identifies the
+// job retried by the job, such that all VCPs agree on the retry, the sharded job the job is a shard of, whether the job
+// has been admitted or failed as decided by the coordinating VCP, whether the job has been cancelled on any VCP, and
+// the VCPs activating the tuple chunk generated by the job.
+type rosterJob struct {
+	klyshkov1alpha1.TupleGenerationJobSpec
+
+	// RetryOf is the name of the failed job retried by the job.
+	RetryOf string `json:"retryOf,omitempty"`
+
+	// Attempt is the number of the retry.
+	Attempt int `json:"attempt,omitempty"`
+
+	// Shard identifies the sharded job the job is a shard of.
+	Shard *jobShard `json:"shard,omitempty"`
+
+	// Admitted tells whether the job has been admitted, i.e., may spawn tasks.
+	Admitted bool `json:"admitted,omitempty"`
+
+	// Failure is the verdict of the coordinating VCP that the job failed independent of the state of its tasks.
+	Failure *jobFailure `json:"failure,omitempty"`
+
+	// Cancelled tells whether the job has been cancelled on request.
+	Cancelled bool `json:"cancelled,omitempty"`
+
+	// Activated lists the players of the VCPs that activated the tuple chunk generated by the job. VCPs record
+	// themselves before activating the tuple chunk, such that verdicts are not recorded anymore afterwards.
+	Activated []uint `json:"activated,omitempty"`
+}
+
+// hasVerdict checks whether the outcome of the job has been decided on independent of the state of its tasks, i.e.,
+// whether it has been cancelled or failed by the coordinating VCP.
+func (j rosterJob) hasVerdict() bool {
+	return j.Cancelled || j.Failure != nil
+}
+
+// hasActivations checks whether the tuple chunk generated by the job has been activated on any VCP, in which case the
+// job can't be cancelled or failed by a verdict anymore.
+func (j rosterJob) hasActivations() bool {
+	return len(j.Activated) > 0
+}
+
+// verdict returns the state of the job decided on independent of the state of its tasks along with the reason and a
+// message. Cancellation takes precedence over failure. To be invoked only in case the job has a verdict.
+func (j rosterJob) verdict() (klyshkov1alpha1.TupleGenerationJobState, string, string) {
+	if j.Cancelled {
+		return klyshkov1alpha1.JobCancelled, klyshkov1alpha1.JobCancellationRequested, "Job cancelled on request"
+	}
+	return klyshkov1alpha1.JobFailed, j.Failure.Reason, j.Failure.Message
+}
+
+// updateRoster replaces the roster stored under the given key with the given one, in case the roster has not been
+// modified since the given revision.
+func (r *TupleGenerationJobReconciler) updateRoster(ctx context.Context, key RosterKey, revision int64, roster *rosterJob) error {
+	encoded, err := json.Marshal(roster)
+	if err != nil {
+		return fmt.Errorf("failed to marshal roster for job %v: %w", key.Name, err)
+	}
+	resp, err := r.EtcdClient.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key.ToEtcdKey()), "=", revision)).
+		Then(clientv3.OpPut(key.ToEtcdKey(), string(encoded))).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("failed to update roster for job %v: %w", key.Name, err)
+	}
+	if !resp.Succeeded {
+		return fmt.Errorf("roster for job %v has been modified concurrently", key.Name)
+	}
+	return nil
+}
+
+// isRetryPending checks whether the given job failed and is going to be retried according to its retry policy. Sharded
+// jobs are not retried as a whole, as their shards are retried individually.
+func isRetryPending(job klyshkov1alpha1.TupleGenerationJob) bool {
+	policy := job.Spec.RetryPolicy
+	return job.Status.State == klyshkov1alpha1.JobFailed && job.Status.RetriedBy == "" && !job.Spec.Cancel &&
+		policy != nil && job.Status.Attempt < policy.MaxRetries && !isSharded(job)
+}
+
+// getRetryBackoff returns the time to wait after a job failed before the given retry attempt (starting at 1) is made.
+func getRetryBackoff(policy klyshkov1alpha1.JobRetryPolicy, attempt int) time.Duration {
+	backoff := time.Duration(policy.InitialBackoffSeconds) * time.Second
+	maxBackoff := time.Duration(policy.MaxBackoffSeconds) * time.Second
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// retryJobName returns the name of the job retrying the given job. Retries of a job are named after the original job
+// suffixed by the number of the retry.
+func retryJobName(job klyshkov1alpha1.TupleGenerationJob) string {
+	root := job.Name
+	if job.Status.Attempt > 0 {
+		root = strings.TrimSuffix(root, fmt.Sprintf("-retry-%d", job.Status.Attempt))
+	}
+	return fmt.Sprintf("%s-retry-%d", root, job.Status.Attempt+1)
+}
+
+// newRetryRoster creates the roster of the job retrying the given job. The retry uses a fresh identifier, i.e., tuple
+// chunk identifier, as the tuple chunk of the failed job might have been partially uploaded to Castor already. Retries
+// of a shard generate the same shard.
+func newRetryRoster(job klyshkov1alpha1.TupleGenerationJob) rosterJob {
+	spec := *job.Spec.DeepCopy()
+	spec.ID = uuid.New().String()
+	spec.Cancel = false
+	return rosterJob{
+		TupleGenerationJobSpec: spec,
+		RetryOf:                job.Name,
+		Attempt:                job.Status.Attempt + 1,
+		Shard:                  getJobShard(job),
+	}
+}
+
+// retryJob retries the given failed job in case its retry policy asks for it and the backoff elapsed. The retry is
+// published in the roster first, as this is what the other VCPs act upon. Afterwards, the job retrying the given one is
+// created locally and linked to the given job. To be invoked on the coordinating VCP only.
+func (r *TupleGenerationJobReconciler) retryJob(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob) (ctrl.Result, error) {
+	if !isRetryPending(*job) {
+		return ctrl.Result{}, nil
+	}
+	logger := r.Logger.WithValues("Job.Name", job.Name)
+	attempt := job.Status.Attempt + 1
+	backoff := getRetryBackoff(*job.Spec.RetryPolicy, attempt)
+	if wait := time.Until(job.Status.LastStateTransitionTime.Add(backoff)); wait > 0 {
+		logger.V(logging.DEBUG).Info("Backing off before retrying job", "Attempt", attempt, "Wait", wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	// Reuse the roster in case it has been created before, such that the retry keeps its identifier
+	name := types.NamespacedName{Namespace: job.Namespace, Name: retryJobName(*job)}
+	key := RosterKey{name}
+	resp, err := r.EtcdClient.Get(ctx, key.ToEtcdKey())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to read roster for retry %v of job %v: %w", name.Name, job.Name, err)
+	}
+	roster := newRetryRoster(*job)
+	if resp.Count > 0 {
+		if err := json.Unmarshal(resp.Kvs[0].Value, &roster); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to unmarshal roster for retry %v of job %v: %w", name.Name, job.Name, err)
+		}
+	} else {
+		encoded, err := json.Marshal(roster)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to marshal roster for retry %v of job %v: %w", name.Name, job.Name, err)
+		}
+		if _, err := r.EtcdClient.Put(ctx, key.ToEtcdKey(), string(encoded)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create roster for retry %v of job %v: %w", name.Name, job.Name, err)
+		}
+		logger.V(logging.DEBUG).Info("Roster for retry created", "Retry.Name", name.Name)
+	}
+
+	// Create the retry controlled by the same scheduler or sharded job as the failed job
+	retry := &klyshkov1alpha1.TupleGenerationJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name.Name,
+			Namespace:       name.Namespace,
+			Labels:          shardLabels(roster.Shard),
+			OwnerReferences: job.OwnerReferences,
+		},
+		Spec: roster.TupleGenerationJobSpec,
+	}
+	if err := r.Create(ctx, retry); err != nil && !apierrors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to create retry %v of job %v: %w", name.Name, job.Name, err)
+	}
+	job.Status.RetriedBy = name.Name
+	if err := r.Status().Update(ctx, job); err != nil {
+		return ctrl.Result{}, fmt.Errorf("status update failed for job %v: %w", job.Name, err)
+	}
+	logger.Info("Job retried", "Retry.Name", name.Name, "Retry.ID", roster.ID, "Attempt", roster.Attempt)
+	return ctrl.Result{}, nil
+}
+
+// linkRetriedJob records the given job as the retry of the job it retries, in case the latter still exists.
+func (r *TupleGenerationJobReconciler) linkRetriedJob(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob) error {
+	retried := &klyshkov1alpha1.TupleGenerationJob{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: job.Status.RetryOf}, retried)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read job %v retried by job %v: %w", job.Status.RetryOf, job.Name, err)
+	}
+	if retried.Status.RetriedBy == job.Name {
+		return nil
+	}
+	retried.Status.RetriedBy = job.Name
+	if err := r.Status().Update(ctx, retried); err != nil {
+		return fmt.Errorf("status update failed for job %v: %w", retried.Name, err)
+	}
+	return nil
+}
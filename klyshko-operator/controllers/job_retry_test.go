@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Describe("Retrying jobs", func() {
+	policy := &klyshkov1alpha1.JobRetryPolicy{MaxRetries: 2, InitialBackoffSeconds: 30, MaxBackoffSeconds: 100}
+	failedJob := func(name string, attempt int) klyshkov1alpha1.TupleGenerationJob {
+		job := finishedJob("A", "generator", klyshkov1alpha1.JobFailed, 0)
+		job.Name = name
+		job.Namespace = "default"
+		job.Spec.ID = "14d8d5e2-2d3c-4b0e-8a6f-1b2f0f0e5a11"
+		job.Spec.Count = 1000
+		job.Spec.RetryPolicy = policy
+		job.Status.Attempt = attempt
+		return job
+	}
+
+	It("retries failed jobs until the maximum number of retries is reached", func() {
+		Expect(isRetryPending(failedJob("job", 0))).To(BeTrue())
+		Expect(isRetryPending(failedJob("job-retry-1", 1))).To(BeTrue())
+		Expect(isRetryPending(failedJob("job-retry-2", 2))).To(BeFalse())
+	})
+
+	It("does not retry jobs without retry policy, that did not fail or have been retried already", func() {
+		job := failedJob("job", 0)
+		job.Spec.RetryPolicy = nil
+		Expect(isRetryPending(job)).To(BeFalse())
+		job = failedJob("job", 0)
+		job.Status.State = klyshkov1alpha1.JobCompleted
+		Expect(isRetryPending(job)).To(BeFalse())
+		job = failedJob("job", 0)
+		job.Status.RetriedBy = "job-retry-1"
+		Expect(isRetryPending(job)).To(BeFalse())
+	})
+
+	It("doubles the backoff with each retry up to the maximum", func() {
+		Expect(getRetryBackoff(*policy, 1)).To(Equal(30 * time.Second))
+		Expect(getRetryBackoff(*policy, 2)).To(Equal(60 * time.Second))
+		Expect(getRetryBackoff(*policy, 3)).To(Equal(100 * time.Second))
+	})
+
+	It("names retries after the original job", func() {
+		Expect(retryJobName(failedJob("job", 0))).To(Equal("job-retry-1"))
+		Expect(retryJobName(failedJob("job-retry-1", 1))).To(Equal("job-retry-2"))
+	})
+
+	It("creates rosters for retries using a fresh tuple chunk identifier", func() {
+		job := failedJob("job-retry-1", 1)
+		roster := newRetryRoster(job)
+		Expect(roster.ID).NotTo(Equal(job.Spec.ID))
+		Expect(roster.Count).To(Equal(job.Spec.Count))
+		Expect(roster.RetryPolicy).To(Equal(policy))
+		Expect(roster.RetryOf).To(Equal("job-retry-1"))
+		Expect(roster.Attempt).To(Equal(2))
+	})
+
+	It("stores rosters compatible with the job specification", func() {
+		job := failedJob("job", 0)
+		encoded, err := json.Marshal(job.Spec)
+		Expect(err).NotTo(HaveOccurred())
+		roster := rosterJob{}
+		Expect(json.Unmarshal(encoded, &roster)).To(Succeed())
+		Expect(roster).To(Equal(rosterJob{TupleGenerationJobSpec: job.Spec}))
+
+		encoded, err = json.Marshal(newRetryRoster(job))
+		Expect(err).NotTo(HaveOccurred())
+		spec := klyshkov1alpha1.TupleGenerationJobSpec{}
+		Expect(json.Unmarshal(encoded, &spec)).To(Succeed())
+		Expect(spec.Count).To(Equal(job.Spec.Count))
+	})
+
+	It("retains failed jobs beyond the TTL until they have been retried", func() {
+		scheduler := newTestScheduler("A")
+		scheduler.Spec.TTLSecondsAfterFinished = 1
+		pending := newTestJob(scheduler, "pending", "A", klyshkov1alpha1.JobFailed)
+		pending.Spec.RetryPolicy = policy
+		retried := newTestJob(scheduler, "retried", "A", klyshkov1alpha1.JobFailed)
+		retried.Spec.RetryPolicy = policy
+		retried.Status.RetriedBy = "retried-retry-1"
+		for _, job := range []*klyshkov1alpha1.TupleGenerationJob{pending, retried} {
+			job.Status.LastStateTransitionTime.Time = time.Now().Add(-time.Minute)
+		}
+		r := newFakeSchedulerReconciler(nil, scheduler, pending, retried)
+
+		Expect(r.cleanupFinishedJobs(context.Background(), scheduler, nil)).To(Succeed())
+		jobs := &klyshkov1alpha1.TupleGenerationJobList{}
+		Expect(r.List(context.Background(), jobs)).To(Succeed())
+		Expect(jobs.Items).To(ConsistOf(HaveField("Name", "pending")))
+	})
+})
@@ -0,0 +1,390 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/logging"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ParentJobLabel identifies the sharded job a shard belongs to.
+	ParentJobLabel = "klyshko.carbnyestack.io/parent-job"
+
+	// ShardIndexLabel identifies the index of a shard within the sharded job it belongs to.
+	ShardIndexLabel = "klyshko.carbnyestack.io/shard-index"
+)
+
+// jobShard identifies a shard of a sharded job.
+type jobShard struct {
+
+	// Parent is the name of the sharded job.
+	Parent string `json:"parent"`
+
+	// Index is the zero-based index of the shard.
+	Index int `json:"index"`
+}
+
+// numberOfShards returns the number of shards the given job is split into, i.e., 1 in case the job is not sharded.
+func numberOfShards(job klyshkov1alpha1.TupleGenerationJob) int {
+	if job.Spec.Sharding == nil || job.Spec.Sharding.Shards < 1 {
+		return 1
+	}
+	if job.Spec.Sharding.Shards > job.Spec.Count {
+		return job.Spec.Count
+	}
+	return job.Spec.Sharding.Shards
+}
+
+// isSharded checks whether the given job is split into multiple shards.
+func isSharded(job klyshkov1alpha1.TupleGenerationJob) bool {
+	return numberOfShards(job) > 1
+}
+
+// shardCount returns the number of tuples generated by the shard with the given index when splitting the given number
+// of tuples evenly across the given number of shards.
+func shardCount(count int, shards int, index int) int {
+	c := count / shards
+	if index < count%shards {
+		c++
+	}
+	return c
+}
+
+// shardJobName returns the name of the job generating the shard with the given index of the given sharded job.
+func shardJobName(parent string, index int) string {
+	return fmt.Sprintf("%s-shard-%d", parent, index)
+}
+
+// shardLabels returns the labels identifying the given shard, or nil in case the shard is nil.
+func shardLabels(shard *jobShard) map[string]string {
+	if shard == nil {
+		return nil
+	}
+	return map[string]string{
+		ParentJobLabel:  shard.Parent,
+		ShardIndexLabel: strconv.Itoa(shard.Index),
+	}
+}
+
+// getJobShard returns the shard generated by the given job as identified by its labels, or nil in case the job is not
+// a shard.
+func getJobShard(job klyshkov1alpha1.TupleGenerationJob) *jobShard {
+	parent, ok := job.Labels[ParentJobLabel]
+	if !ok {
+		return nil
+	}
+	index, err := strconv.Atoi(job.Labels[ShardIndexLabel])
+	if err != nil {
+		return nil
+	}
+	return &jobShard{Parent: parent, Index: index}
+}
+
+// newShardRoster creates the roster of the shard with the given index of the given sharded job. Each shard uses a
+// fresh identifier, i.e., tuple chunk identifier. The shards inherit the priority, the retry policy and the task
+// timeouts of the sharded job, whereas the deadline applies to the sharded job as a whole.
+func newShardRoster(job klyshkov1alpha1.TupleGenerationJob, index int) rosterJob {
+	return rosterJob{
+		TupleGenerationJobSpec: klyshkov1alpha1.TupleGenerationJobSpec{
+			ID:           uuid.New().String(),
+			Type:         job.Spec.Type,
+			Count:        shardCount(job.Spec.Count, numberOfShards(job), index),
+			Generator:    job.Spec.Generator,
+			Priority:     job.Spec.Priority,
+			RetryPolicy:  job.Spec.RetryPolicy.DeepCopy(),
+			TaskTimeouts: job.Spec.TaskTimeouts.DeepCopy(),
+		},
+		Shard: &jobShard{Parent: job.Name, Index: index},
+	}
+}
+
+// currentShards returns the jobs currently generating the shards out of the given shard jobs indexed by shard, i.e.,
+// ignoring jobs that have been superseded by a retry.
+func currentShards(shards []klyshkov1alpha1.TupleGenerationJob) map[int]klyshkov1alpha1.TupleGenerationJob {
+	current := map[int]klyshkov1alpha1.TupleGenerationJob{}
+	for _, s := range shards {
+		shard := getJobShard(s)
+		if shard == nil || s.Status.RetriedBy != "" {
+			continue
+		}
+		if c, ok := current[shard.Index]; ok && c.Status.Attempt >= s.Status.Attempt {
+			continue
+		}
+		current[shard.Index] = s
+	}
+	return current
+}
+
+// isShardActive checks whether the given shard job is still running or is going to be retried.
+func isShardActive(shard klyshkov1alpha1.TupleGenerationJob) bool {
+	return !shard.Status.State.IsDone() || isRetryPending(shard)
+}
+
+// shardedJobState derives the state of a sharded job from the given current shard jobs out of the given number of
+// shards. The job fails as soon as any of its shards failed without being retried anymore or has been cancelled, and
+// completes as soon as all of its shards completed, i.e., their tuple chunks have been activated.
+func shardedJobState(current map[int]klyshkov1alpha1.TupleGenerationJob, shards int) (klyshkov1alpha1.TupleGenerationJobState, string, string) {
+	var failures []string
+	completed := 0
+	for i := 0; i < shards; i++ {
+		shard, ok := current[i]
+		if !ok {
+			continue
+		}
+		switch {
+		case shard.Status.State == klyshkov1alpha1.JobCompleted:
+			completed++
+		case !isShardActive(shard):
+			failure := fmt.Sprintf("Shard %d (%s) %s", i, shard.Name, strings.ToLower(string(shard.Status.State)))
+			if shard.Status.Reason != "" {
+				failure += fmt.Sprintf(" (%s)", shard.Status.Reason)
+			}
+			if shard.Status.Message != "" {
+				failure += ": " + shard.Status.Message
+			}
+			failures = append(failures, failure)
+		}
+	}
+	switch {
+	case len(failures) > 0:
+		return klyshkov1alpha1.JobFailed, klyshkov1alpha1.JobShardFailed, strings.Join(failures, "; ")
+	case completed == shards:
+		return klyshkov1alpha1.JobCompleted, "", ""
+	case len(current) == 0:
+		return klyshkov1alpha1.JobPending, "", ""
+	default:
+		return klyshkov1alpha1.JobRunning, "", ""
+	}
+}
+
+// summarizeShards summarizes the given current shard jobs ordered by index.
+func summarizeShards(current map[int]klyshkov1alpha1.TupleGenerationJob) []klyshkov1alpha1.ShardStatus {
+	var summaries []klyshkov1alpha1.ShardStatus
+	for index, shard := range current {
+		summaries = append(summaries, klyshkov1alpha1.ShardStatus{
+			Index: index,
+			Job:   shard.Name,
+			ID:    shard.Spec.ID,
+			Count: shard.Spec.Count,
+			State: shard.Status.State,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Index < summaries[j].Index
+	})
+	return summaries
+}
+
+// updateShardedJobStatus updates the timestamps, the shard summary and the conditions of the given sharded job
+// according to its state and the given current shard jobs out of the given number of shards.
+func updateShardedJobStatus(job *klyshkov1alpha1.TupleGenerationJob, current map[int]klyshkov1alpha1.TupleGenerationJob, shards int) {
+	if job.Status.StartTime == nil {
+		now := metav1.Now()
+		job.Status.StartTime = &now
+	}
+	if job.Status.State == klyshkov1alpha1.JobCompleted && job.Status.CompletionTime == nil {
+		completed := job.Status.LastStateTransitionTime
+		job.Status.CompletionTime = &completed
+	}
+	job.Status.Shards = summarizeShards(current)
+	completed := 0
+	for _, s := range job.Status.Shards {
+		if s.State == klyshkov1alpha1.JobCompleted {
+			completed++
+		}
+	}
+	job.Status.Progress = fmt.Sprintf("%d/%d", completed, shards)
+
+	if len(current) < shards {
+		setJobCondition(job, klyshkov1alpha1.JobShardsCreated, metav1.ConditionFalse, "WaitingForShards",
+			fmt.Sprintf("%d of %d shards created", len(current), shards))
+	} else {
+		setJobCondition(job, klyshkov1alpha1.JobShardsCreated, metav1.ConditionTrue, "AllShardsCreated",
+			fmt.Sprintf("All %d shards created", shards))
+	}
+	if completed < shards {
+		setJobCondition(job, klyshkov1alpha1.JobTupleChunkActivated, metav1.ConditionFalse, "WaitingForShards",
+			fmt.Sprintf("Tuple chunks of %d of %d shards activated", completed, shards))
+	} else {
+		setJobCondition(job, klyshkov1alpha1.JobTupleChunkActivated, metav1.ConditionTrue, "AllShardsActivated",
+			"Tuple chunks of all shards activated")
+	}
+	setFinishedCondition(job)
+}
+
+// jobShards returns all jobs generating shards of the given sharded job, including the ones superseded by a retry.
+func (r *TupleGenerationJobReconciler) jobShards(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob) ([]klyshkov1alpha1.TupleGenerationJob, error) {
+	shards := &klyshkov1alpha1.TupleGenerationJobList{}
+	err := r.List(ctx, shards, client.InNamespace(job.Namespace), client.MatchingLabels{ParentJobLabel: job.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch shards of job %v: %w", job.Name, err)
+	}
+	return shards.Items, nil
+}
+
+// reconcileShardedJob reconciles the given sharded job. Instead of spawning tasks, the coordinating VCP creates the
+// shards of the job, each of which is a job of its own that is propagated to the other VCPs via its roster. The state
+// of the job is derived from the states of its shards. Once the job failed or has been cancelled, the coordinating VCP
+// cancels the shards still running.
+func (r *TupleGenerationJobReconciler) reconcileShardedJob(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob, roster rosterJob, playerID uint, untilDeadline time.Duration) (ctrl.Result, error) {
+	logger := r.Logger.WithValues("Job.Name", job.Name)
+	shardJobs, err := r.jobShards(ctx, job)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	current := currentShards(shardJobs)
+	shards := numberOfShards(*job)
+
+	// Verdicts are ignored as soon as all shards completed, as their tuple chunks have been activated already
+	status := job.Status.DeepCopy()
+	if !job.Status.State.IsDone() {
+		state, reason, message := shardedJobState(current, shards)
+		if state != klyshkov1alpha1.JobCompleted && roster.hasVerdict() {
+			state, reason, message = roster.verdict()
+		}
+		if state != job.Status.State {
+			logger.V(logging.DEBUG).Info("State update", "from", job.Status.State, "to", state)
+			job.Status.State = state
+			job.Status.LastStateTransitionTime = metav1.Now()
+			job.Status.Reason, job.Status.Message = reason, message
+		}
+	}
+	updateShardedJobStatus(job, current, shards)
+	if !equality.Semantic.DeepEqual(*status, job.Status) {
+		if err := r.Status().Update(ctx, job); err != nil {
+			return ctrl.Result{}, fmt.Errorf("status update failed for job %v: %w", job.Name, err)
+		}
+	}
+
+	// Shards are created and cancelled by the coordinator only
+	if playerID != coordinatorPlayerID {
+		return ctrl.Result{}, nil
+	}
+	switch job.Status.State {
+	case klyshkov1alpha1.JobFailed, klyshkov1alpha1.JobCancelled:
+		return ctrl.Result{}, r.cancelShards(ctx, current)
+	case klyshkov1alpha1.JobCompleted:
+		return ctrl.Result{}, nil
+	}
+	if err := r.createShards(ctx, job, current, shards); err != nil {
+		return ctrl.Result{}, err
+	}
+	if untilDeadline > 0 {
+		return ctrl.Result{RequeueAfter: untilDeadline}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// createShards creates the shards of the given sharded job that have not been created yet out of the given number of
+// shards, as long as the number of active shards stays within the bound given in the sharding specification.
+func (r *TupleGenerationJobReconciler) createShards(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob, current map[int]klyshkov1alpha1.TupleGenerationJob, shards int) error {
+	active := 0
+	for _, shard := range current {
+		if isShardActive(shard) {
+			active++
+		}
+	}
+	maxParallel := job.Spec.Sharding.MaxParallelShards
+	for i := 0; i < shards; i++ {
+		if _, ok := current[i]; ok {
+			continue
+		}
+		if maxParallel > 0 && active >= maxParallel {
+			break
+		}
+		if err := r.createShard(ctx, job, i); err != nil {
+			return err
+		}
+		active++
+	}
+	return nil
+}
+
+// createShard creates the shard with the given index of the given sharded job. The shard is published in its roster
+// first, as this is what the other VCPs act upon. To be invoked on the coordinating VCP only.
+func (r *TupleGenerationJobReconciler) createShard(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob, index int) error {
+	// Reuse the roster in case it has been created before, such that the shard keeps its identifier
+	name := types.NamespacedName{Namespace: job.Namespace, Name: shardJobName(job.Name, index)}
+	key := RosterKey{name}
+	resp, err := r.EtcdClient.Get(ctx, key.ToEtcdKey())
+	if err != nil {
+		return fmt.Errorf("failed to read roster for shard %v of job %v: %w", name.Name, job.Name, err)
+	}
+	roster := newShardRoster(*job, index)
+	if resp.Count > 0 {
+		if err := json.Unmarshal(resp.Kvs[0].Value, &roster); err != nil {
+			return fmt.Errorf("failed to unmarshal roster for shard %v of job %v: %w", name.Name, job.Name, err)
+		}
+	} else {
+		encoded, err := json.Marshal(roster)
+		if err != nil {
+			return fmt.Errorf("failed to marshal roster for shard %v of job %v: %w", name.Name, job.Name, err)
+		}
+		if _, err := r.EtcdClient.Put(ctx, key.ToEtcdKey(), string(encoded)); err != nil {
+			return fmt.Errorf("failed to create roster for shard %v of job %v: %w", name.Name, job.Name, err)
+		}
+	}
+	if err := r.createJobIfNotExists(ctx, name, &roster); err != nil {
+		return fmt.Errorf("failed to create shard %v of job %v: %w", name.Name, job.Name, err)
+	}
+	r.Logger.Info("Shard created", "Job.Name", job.Name, "Shard.Name", name.Name, "Shard.ID", roster.ID,
+		"Shard.Count", roster.Count)
+	return nil
+}
+
+// cancelShards requests the cancellation of the given shard jobs that are still running or going to be retried.
+func (r *TupleGenerationJobReconciler) cancelShards(ctx context.Context, current map[int]klyshkov1alpha1.TupleGenerationJob) error {
+	for _, shard := range current {
+		if shard.Spec.Cancel || !isShardActive(shard) {
+			continue
+		}
+		shard.Spec.Cancel = true
+		if err := r.Update(ctx, &shard); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to cancel shard %v: %w", shard.Name, err)
+		}
+		r.Logger.V(logging.DEBUG).Info("Shard cancellation requested", "Shard.Name", shard.Name)
+	}
+	return nil
+}
+
+// tuplesGenerated checks whether the tuples of the given job have been generated on all VCPs, i.e., whether its tasks
+// completed on all VCPs or, in case the job is sharded, all of its shards completed.
+func (r *TupleGenerationJobReconciler) tuplesGenerated(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob) (bool, error) {
+	if isSharded(*job) {
+		shards, err := r.jobShards(ctx, job)
+		if err != nil {
+			return false, err
+		}
+		state, _, _ := shardedJobState(currentShards(shards), numberOfShards(*job))
+		return state == klyshkov1alpha1.JobCompleted, nil
+	}
+	tasks, err := r.ownedTasks(ctx, job)
+	if err != nil {
+		return false, err
+	}
+	numberOfVCPs, err := numberOfVCPs(ctx, &r.Client, job.Namespace)
+	if err != nil {
+		return false, fmt.Errorf("can't read playerCount from VCP configuration: %w", err)
+	}
+	return allCompleted(tasks, numberOfVCPs), nil
+}
@@ -0,0 +1,207 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Sharding jobs", func() {
+	shardedJob := func(count int, shards int) *klyshkov1alpha1.TupleGenerationJob {
+		job := newTestJob(newTestScheduler("A"), "job", "A", klyshkov1alpha1.JobRunning)
+		job.Spec.Count = count
+		job.Spec.Sharding = &klyshkov1alpha1.JobShardingSpec{Shards: shards}
+		job.Spec.RetryPolicy = &klyshkov1alpha1.JobRetryPolicy{MaxRetries: 1}
+		return job
+	}
+	shard := func(name string, index int, state klyshkov1alpha1.TupleGenerationJobState) *klyshkov1alpha1.TupleGenerationJob {
+		job := &klyshkov1alpha1.TupleGenerationJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    shardLabels(&jobShard{Parent: "job", Index: index}),
+			},
+			Spec:   klyshkov1alpha1.TupleGenerationJobSpec{ID: name, Type: "A", Count: 100, Generator: "generator"},
+			Status: klyshkov1alpha1.TupleGenerationJobStatus{State: state},
+		}
+		return job
+	}
+	currentOf := func(shards ...*klyshkov1alpha1.TupleGenerationJob) map[int]klyshkov1alpha1.TupleGenerationJob {
+		var jobs []klyshkov1alpha1.TupleGenerationJob
+		for _, s := range shards {
+			jobs = append(jobs, *s)
+		}
+		return currentShards(jobs)
+	}
+
+	It("splits the tuples evenly across at most as many shards as tuples", func() {
+		Expect(numberOfShards(*newTestJob(newTestScheduler("A"), "job", "A", klyshkov1alpha1.JobRunning))).To(Equal(1))
+		Expect(isSharded(*shardedJob(1000, 1))).To(BeFalse())
+		Expect(numberOfShards(*shardedJob(2, 3))).To(Equal(2))
+
+		job := shardedJob(1000, 3)
+		Expect(isSharded(*job)).To(BeTrue())
+		Expect([]int{shardCount(1000, 3, 0), shardCount(1000, 3, 1), shardCount(1000, 3, 2)}).To(Equal([]int{334, 333, 333}))
+		roster := newShardRoster(*job, 2)
+		Expect(roster.ID).NotTo(Equal(job.Spec.ID))
+		Expect(roster.Count).To(Equal(333))
+		Expect(roster.Sharding).To(BeNil())
+		Expect(roster.RetryPolicy).To(Equal(job.Spec.RetryPolicy))
+		Expect(roster.Shard).To(Equal(&jobShard{Parent: "job", Index: 2}))
+		Expect(shardJobName("job", 2)).To(Equal("job-shard-2"))
+	})
+
+	It("keeps retries of shards within the sharded job", func() {
+		failed := shard("job-shard-1", 1, klyshkov1alpha1.JobFailed)
+		failed.Spec.RetryPolicy = &klyshkov1alpha1.JobRetryPolicy{MaxRetries: 1}
+		Expect(isRetryPending(*failed)).To(BeTrue())
+		roster := newRetryRoster(*failed)
+		Expect(roster.Shard).To(Equal(&jobShard{Parent: "job", Index: 1}))
+
+		failed.Status.RetriedBy = "job-shard-1-retry-1"
+		retry := shard("job-shard-1-retry-1", 1, klyshkov1alpha1.JobRunning)
+		retry.Status.Attempt = 1
+		current := currentOf(failed, retry, shard("other", 0, klyshkov1alpha1.JobRunning))
+		Expect(current).To(HaveLen(2))
+		Expect(current[1].Name).To(Equal("job-shard-1-retry-1"))
+	})
+
+	It("does not retry sharded jobs as a whole", func() {
+		job := shardedJob(1000, 2)
+		job.Status.State = klyshkov1alpha1.JobFailed
+		Expect(isRetryPending(*job)).To(BeFalse())
+	})
+
+	It("derives the state of the sharded job from its shards", func() {
+		state, _, _ := shardedJobState(currentOf(), 2)
+		Expect(state).To(Equal(klyshkov1alpha1.JobPending))
+		state, _, _ = shardedJobState(currentOf(shard("s0", 0, klyshkov1alpha1.JobCompleted)), 2)
+		Expect(state).To(Equal(klyshkov1alpha1.JobRunning))
+
+		pending := shard("s1", 1, klyshkov1alpha1.JobFailed)
+		pending.Spec.RetryPolicy = &klyshkov1alpha1.JobRetryPolicy{MaxRetries: 1}
+		state, _, _ = shardedJobState(currentOf(shard("s0", 0, klyshkov1alpha1.JobCompleted), pending), 2)
+		Expect(state).To(Equal(klyshkov1alpha1.JobRunning))
+
+		state, _, _ = shardedJobState(currentOf(shard("s0", 0, klyshkov1alpha1.JobCompleted),
+			shard("s1", 1, klyshkov1alpha1.JobCompleted)), 2)
+		Expect(state).To(Equal(klyshkov1alpha1.JobCompleted))
+
+		failed := shard("s1", 1, klyshkov1alpha1.JobFailed)
+		failed.Status.Reason, failed.Status.Message = klyshkov1alpha1.JobTaskFailed, "Task of player 1 failed"
+		state, reason, message := shardedJobState(currentOf(shard("s0", 0, klyshkov1alpha1.JobRunning), failed), 2)
+		Expect(state).To(Equal(klyshkov1alpha1.JobFailed))
+		Expect(reason).To(Equal(klyshkov1alpha1.JobShardFailed))
+		Expect(message).To(Equal("Shard 1 (s1) failed (TaskFailed): Task of player 1 failed"))
+	})
+
+	It("reports the shards in the status of the sharded job", func() {
+		job := shardedJob(1000, 3)
+		updateShardedJobStatus(job, currentOf(shard("s1", 1, klyshkov1alpha1.JobRunning),
+			shard("s0", 0, klyshkov1alpha1.JobCompleted)), 3)
+		Expect(job.Status.Progress).To(Equal("1/3"))
+		Expect(job.Status.Shards).To(Equal([]klyshkov1alpha1.ShardStatus{
+			{Index: 0, Job: "s0", ID: "s0", Count: 100, State: klyshkov1alpha1.JobCompleted},
+			{Index: 1, Job: "s1", ID: "s1", Count: 100, State: klyshkov1alpha1.JobRunning},
+		}))
+		Expect(meta.IsStatusConditionFalse(job.Status.Conditions, klyshkov1alpha1.JobShardsCreated)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(job.Status.Conditions, klyshkov1alpha1.JobTupleChunkActivated)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(job.Status.Conditions, klyshkov1alpha1.JobFinished)).To(BeTrue())
+	})
+
+	It("aggregates the shards on VCPs other than the coordinator", func() {
+		job := shardedJob(200, 2)
+		job.Status.State = klyshkov1alpha1.JobPending
+		c := newFakeSchedulerReconciler(nil, job, shard("job-shard-0", 0, klyshkov1alpha1.JobCompleted),
+			shard("job-shard-1", 1, klyshkov1alpha1.JobCompleted)).Client
+		r := &TupleGenerationJobReconciler{Client: c, Logger: logr.Discard()}
+		_, err := r.reconcileShardedJob(context.Background(), job, rosterJob{Cancelled: true}, 1, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &klyshkov1alpha1.TupleGenerationJob{}
+		Expect(r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "job"}, updated)).To(Succeed())
+		Expect(updated.Status.State).To(Equal(klyshkov1alpha1.JobCompleted))
+		Expect(updated.Status.Progress).To(Equal("2/2"))
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, klyshkov1alpha1.JobTupleChunkActivated)).To(BeTrue())
+		generated, err := r.tuplesGenerated(context.Background(), updated)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generated).To(BeTrue())
+	})
+
+	It("mirrors shards as controlled by the sharded job on VCPs other than the coordinator", func() {
+		job := shardedJob(200, 2)
+		fake := newFakeSchedulerReconcilerForPlayer(1, nil, job)
+		r := &TupleGenerationJobReconciler{Client: fake.Client, Scheme: fake.Scheme, Logger: logr.Discard()}
+		roster := newShardRoster(*job, 1)
+		name := types.NamespacedName{Namespace: "default", Name: "job-shard-1"}
+		Expect(r.createJobIfNotExists(context.Background(), name, &roster)).To(Succeed())
+		Expect(r.createJobIfNotExists(context.Background(), name, &roster)).To(Succeed())
+
+		s := &klyshkov1alpha1.TupleGenerationJob{}
+		Expect(r.Get(context.Background(), name, s)).To(Succeed())
+		Expect(getJobShard(*s)).To(Equal(&jobShard{Parent: "job", Index: 1}))
+		Expect(metav1.IsControlledBy(s, job)).To(BeTrue())
+	})
+
+	It("cancels the remaining shards of a cancelled job on the coordinator", func() {
+		job := shardedJob(200, 2)
+		c := newFakeSchedulerReconciler(nil, job, shard("job-shard-0", 0, klyshkov1alpha1.JobCompleted),
+			shard("job-shard-1", 1, klyshkov1alpha1.JobRunning)).Client
+		r := &TupleGenerationJobReconciler{Client: c, Logger: logr.Discard()}
+		_, err := r.reconcileShardedJob(context.Background(), job, rosterJob{Cancelled: true}, coordinatorPlayerID, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(job.Status.State).To(Equal(klyshkov1alpha1.JobCancelled))
+		for name, cancelled := range map[string]bool{"job-shard-0": false, "job-shard-1": true} {
+			s := &klyshkov1alpha1.TupleGenerationJob{}
+			Expect(r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, s)).To(Succeed())
+			Expect(s.Spec.Cancel).To(Equal(cancelled))
+		}
+	})
+
+	It("skips roster events that keep failing to be processed after replaying them", func() {
+		event := func(key string, revision int64) *clientv3.Event {
+			return &clientv3.Event{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte(key), ModRevision: revision}}
+		}
+		events := []*clientv3.Event{event("/ok-1", 7), event("/broken", 8), event("/ok-2", 9)}
+		processed := map[string]int{}
+		handle := func(ctx context.Context, ev *clientv3.Event) error {
+			processed[string(ev.Kv.Key)]++
+			if string(ev.Kv.Key) == "/broken" {
+				return errors.New("broken")
+			}
+			return nil
+		}
+		r := &TupleGenerationJobReconciler{Logger: logr.Discard()}
+		replays := map[int64]int{}
+
+		for i := 0; i < maxEventReplays; i++ {
+			head, replay := r.processWatchEvents(context.Background(), events, 9, replays, handle)
+			Expect(replay).To(BeTrue())
+			Expect(head).To(Equal(int64(7)))
+		}
+		Expect(processed["/ok-2"]).To(BeZero())
+
+		head, replay := r.processWatchEvents(context.Background(), events, 9, replays, handle)
+		Expect(replay).To(BeFalse())
+		Expect(head).To(Equal(int64(9)))
+		Expect(processed["/broken"]).To(Equal(maxEventReplays + 1))
+		Expect(processed["/ok-2"]).To(Equal(1))
+		Expect(replays).To(BeEmpty())
+	})
+})
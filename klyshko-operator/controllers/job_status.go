@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
+)
+
+// summarizeTasks summarizes the states of the given tasks of a job ordered by player.
+func summarizeTasks(tasks []klyshkov1alpha1.TupleGenerationTask) []klyshkov1alpha1.TaskSummary {
+	var summaries []klyshkov1alpha1.TaskSummary
+	for _, t := range tasks {
+		summaries = append(summaries, klyshkov1alpha1.TaskSummary{
+			PlayerID: t.Spec.PlayerID,
+			State:    t.Status.State,
+			Endpoint: t.Status.Endpoint,
+			Reason:   t.Status.Reason,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].PlayerID < summaries[j].PlayerID
+	})
+	return summaries
+}
+
+// jobProgress returns the number of the given tasks that completed successfully out of the given number of VCPs.
+func jobProgress(tasks []klyshkov1alpha1.TupleGenerationTask, numberOfVCPs uint) string {
+	completed := 0
+	for _, t := range tasks {
+		if t.Status.State == klyshkov1alpha1.TaskCompleted {
+			completed++
+		}
+	}
+	return fmt.Sprintf("%d/%d", completed, numberOfVCPs)
+}
+
+// setJobCondition sets the condition of the given type on the given job.
+func setJobCondition(job *klyshkov1alpha1.TupleGenerationJob, conditionType string, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&job.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: job.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// updateJobStatus updates the timestamps, the task summary and the conditions of the given job according to its state
+// and the given tasks owned by the job.
+func updateJobStatus(job *klyshkov1alpha1.TupleGenerationJob, tasks []klyshkov1alpha1.TupleGenerationTask, numberOfVCPs uint) {
+	if job.Status.StartTime == nil {
+		now := metav1.Now()
+		job.Status.StartTime = &now
+	}
+	if job.Status.State == klyshkov1alpha1.JobCompleted && job.Status.CompletionTime == nil {
+		completed := job.Status.LastStateTransitionTime
+		job.Status.CompletionTime = &completed
+	}
+	job.Status.Progress = jobProgress(tasks, numberOfVCPs)
+	job.Status.Tasks = summarizeTasks(tasks)
+
+	if uint(len(tasks)) < numberOfVCPs {
+		setJobCondition(job, klyshkov1alpha1.JobTasksSpawned, metav1.ConditionFalse, "WaitingForTasks",
+			fmt.Sprintf("%d of %d tasks spawned", len(tasks), numberOfVCPs))
+	} else {
+		setJobCondition(job, klyshkov1alpha1.JobTasksSpawned, metav1.ConditionTrue, "AllTasksSpawned",
+			"Tasks spawned on all VCPs")
+	}
+	setFinishedCondition(job)
+}
+
+// setFinishedCondition sets the condition signalling whether the given job is done according to its state.
+func setFinishedCondition(job *klyshkov1alpha1.TupleGenerationJob) {
+	switch job.Status.State {
+	case klyshkov1alpha1.JobCompleted:
+		setJobCondition(job, klyshkov1alpha1.JobFinished, metav1.ConditionTrue, string(job.Status.State),
+			"Job completed successfully")
+	case klyshkov1alpha1.JobFailed, klyshkov1alpha1.JobCancelled:
+		reason := job.Status.Reason
+		if reason == "" {
+			reason = string(job.Status.State)
+		}
+		setJobCondition(job, klyshkov1alpha1.JobFinished, metav1.ConditionTrue, reason, job.Status.Message)
+	default:
+		setJobCondition(job, klyshkov1alpha1.JobFinished, metav1.ConditionFalse, string(job.Status.State),
+			fmt.Sprintf("Job is %s", job.Status.State))
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Reporting the job status", func() {
+	task := func(playerID uint, state klyshkov1alpha1.TupleGenerationTaskState, endpoint string) klyshkov1alpha1.TupleGenerationTask {
+		return klyshkov1alpha1.TupleGenerationTask{
+			Spec: klyshkov1alpha1.TupleGenerationTaskSpec{PlayerID: playerID},
+			Status: klyshkov1alpha1.TupleGenerationTaskStatus{
+				State:    state,
+				Endpoint: endpoint,
+			},
+		}
+	}
+	condition := func(job *klyshkov1alpha1.TupleGenerationJob, conditionType string) *metav1.Condition {
+		c := meta.FindStatusCondition(job.Status.Conditions, conditionType)
+		Expect(c).NotTo(BeNil())
+		return c
+	}
+
+	It("summarizes the tasks ordered by player", func() {
+		tasks := []klyshkov1alpha1.TupleGenerationTask{
+			task(1, klyshkov1alpha1.TaskGenerating, "10.0.0.2:5000"),
+			task(0, klyshkov1alpha1.TaskCompleted, "10.0.0.1:5000"),
+		}
+		Expect(summarizeTasks(tasks)).To(Equal([]klyshkov1alpha1.TaskSummary{
+			{PlayerID: 0, State: klyshkov1alpha1.TaskCompleted, Endpoint: "10.0.0.1:5000"},
+			{PlayerID: 1, State: klyshkov1alpha1.TaskGenerating, Endpoint: "10.0.0.2:5000"},
+		}))
+		Expect(jobProgress(tasks, 3)).To(Equal("1/3"))
+	})
+
+	It("reports jobs waiting for tasks", func() {
+		job := newTestJob(newTestScheduler("A"), "job", "A", klyshkov1alpha1.JobPending)
+		updateJobStatus(job, []klyshkov1alpha1.TupleGenerationTask{task(0, klyshkov1alpha1.TaskPreparing, "")}, 2)
+		Expect(job.Status.StartTime).NotTo(BeNil())
+		Expect(job.Status.CompletionTime).To(BeNil())
+		Expect(job.Status.Progress).To(Equal("0/2"))
+		Expect(condition(job, klyshkov1alpha1.JobTasksSpawned).Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition(job, klyshkov1alpha1.JobFinished).Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("reports completed and failed jobs as finished", func() {
+		tasks := []klyshkov1alpha1.TupleGenerationTask{
+			task(0, klyshkov1alpha1.TaskCompleted, ""),
+			task(1, klyshkov1alpha1.TaskCompleted, ""),
+		}
+		job := newTestJob(newTestScheduler("A"), "job", "A", klyshkov1alpha1.JobCompleted)
+		updateJobStatus(job, tasks, 2)
+		Expect(job.Status.CompletionTime).To(Equal(&job.Status.LastStateTransitionTime))
+		Expect(job.Status.Progress).To(Equal("2/2"))
+		Expect(condition(job, klyshkov1alpha1.JobTasksSpawned).Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition(job, klyshkov1alpha1.JobFinished).Status).To(Equal(metav1.ConditionTrue))
+
+		job = newTestJob(newTestScheduler("A"), "job", "A", klyshkov1alpha1.JobFailed)
+		job.Status.Reason = klyshkov1alpha1.JobDeadlineExceeded
+		updateJobStatus(job, tasks, 2)
+		Expect(job.Status.CompletionTime).To(BeNil())
+		Expect(condition(job, klyshkov1alpha1.JobFinished).Reason).To(Equal(klyshkov1alpha1.JobDeadlineExceeded))
+	})
+})
@@ -0,0 +1,58 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// schedulerBursting signals whether a scheduler is in burst mode.
+	schedulerBursting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "klyshko_scheduler_bursting",
+		Help: "Whether the scheduler is in burst mode (1) or not (0).",
+	}, []string{"namespace", "scheduler"})
+
+	// schedulerBurstEpisodes counts the burst episodes of a scheduler.
+	schedulerBurstEpisodes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "klyshko_scheduler_burst_episodes_total",
+		Help: "Number of times the scheduler entered burst mode.",
+	}, []string{"namespace", "scheduler"})
+
+	// schedulerBurstJobs counts the jobs created by a scheduler beyond its regular concurrency.
+	schedulerBurstJobs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "klyshko_scheduler_burst_jobs_total",
+		Help: "Number of jobs created beyond the regular concurrency of the scheduler.",
+	}, []string{"namespace", "scheduler"})
+
+	// garbageCollectionOrphans counts the orphans of each kind found by the last garbage collection sweep.
+	garbageCollectionOrphans = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "klyshko_gc_orphans",
+		Help: "Number of orphaned etcd keys and resources found by the last garbage collection sweep.",
+	}, []string{"kind"})
+
+	// garbageCollectionDeletions counts the orphans of each kind deleted by the garbage collector.
+	garbageCollectionDeletions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "klyshko_gc_deleted_total",
+		Help: "Number of orphaned etcd keys and resources deleted by the garbage collector.",
+	}, []string{"kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(schedulerBursting, schedulerBurstEpisodes, schedulerBurstJobs,
+		garbageCollectionOrphans, garbageCollectionDeletions)
+}
+
+// forgetSchedulerMetrics removes the metrics of the scheduler with the given name in the given namespace.
+func forgetSchedulerMetrics(namespace string, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "scheduler": name}
+	schedulerBursting.Delete(labels)
+	schedulerBurstEpisodes.Delete(labels)
+	schedulerBurstJobs.Delete(labels)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	"github.com/carbynestack/klyshko/logging"
+	"math"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+const (
+	// PredictiveSchedulingStrategyName is the name under which the PredictiveSchedulingStrategy is registered.
+	PredictiveSchedulingStrategyName = "Predictive"
+
+	// predictiveHorizonParameter is the name of the parameter used to configure the horizon of the
+	// PredictiveSchedulingStrategy.
+	predictiveHorizonParameter = "horizon"
+
+	// defaultPredictiveHorizon is the horizon used by the PredictiveSchedulingStrategy in case none is given.
+	defaultPredictiveHorizon = 15 * time.Minute
+)
+
+// PredictiveSchedulingStrategy selects the tuple type that is expected to be exhausted first. The time to exhaustion
+// is estimated per tuple type by dividing the number of available and in-flight tuples by the consumption rate (in
+// tuples per second) reported by Castor. A tuple type is eligible if it is either below its threshold or expected to be
+// exhausted within the configured horizon. Among the eligible tuple types the one with the shortest time to exhaustion
+// is selected. Ties, e.g., between tuple types that are not consumed at all, are broken by priority.
+type PredictiveSchedulingStrategy struct {
+	// Horizon is the time span within which a tuple type must be expected to run dry to be eligible for scheduling
+	// even though it is above its threshold.
+	Horizon time.Duration
+}
+
+// NewPredictiveSchedulingStrategy creates a PredictiveSchedulingStrategy. The optional `horizon` parameter can be used
+// to override the default horizon of 15 minutes. It must be given in a format accepted by time.ParseDuration.
+func NewPredictiveSchedulingStrategy(parameters map[string]string) (SchedulingStrategy, error) {
+	if err := checkParameters(parameters, predictiveHorizonParameter); err != nil {
+		return nil, err
+	}
+	strategy := &PredictiveSchedulingStrategy{
+		Horizon: defaultPredictiveHorizon,
+	}
+	if horizonStr, ok := parameters[predictiveHorizonParameter]; ok {
+		horizon, err := time.ParseDuration(horizonStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid horizon '%s': %w", horizonStr, err)
+		}
+		if horizon < 0 {
+			return nil, fmt.Errorf("invalid horizon '%s' - must not be negative", horizonStr)
+		}
+		strategy.Horizon = horizon
+	}
+	return strategy, nil
+}
+
+// timeToExhaustion estimates the time until the given number of tuples is used up at the given consumption rate. In
+// case tuples are not consumed at all, the maximum duration is returned.
+func timeToExhaustion(available int, consumptionRate int) time.Duration {
+	if consumptionRate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	if available <= 0 {
+		return 0
+	}
+	seconds := float64(available) / float64(consumptionRate)
+	if seconds >= time.Duration(math.MaxInt64).Seconds() {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Schedule returns the tuple type selected by this strategy or `nil` if none is eligible.
+func (s *PredictiveSchedulingStrategy) Schedule(
+	ctx context.Context, telemetry castor.Telemetry, policies []v1alpha1.TupleTypePolicy,
+	activeJobs []v1alpha1.TupleGenerationJob) (*string, error) {
+
+	logger := log.FromContext(ctx)
+
+	// Compute aggregate of available and in-flight tuples
+	t := withInflightTuples(telemetry, activeJobs)
+
+	// Create map of policies by tuple type
+	policyByType := map[string]v1alpha1.TupleTypePolicy{}
+	for _, policy := range policies {
+		policyByType[policy.Type] = policy
+	}
+
+	// Select the eligible tuple type closest to running dry
+	var selected *v1alpha1.TupleTypePolicy
+	var selectedTimeToExhaustion time.Duration
+	for _, m := range t.TupleMetrics {
+		policy, exists := policyByType[m.TupleType]
+		if !exists {
+			continue
+		}
+		tte := timeToExhaustion(m.Available, m.ConsumptionRate)
+		logger.V(logging.DEBUG).Info("Estimated time to exhaustion", "TupleType", m.TupleType,
+			"Available.WithInflight", m.Available, "ConsumptionRate", m.ConsumptionRate, "TimeToExhaustion", tte)
+		if m.Available >= policy.Threshold && tte >= s.Horizon {
+			continue
+		}
+		if selected == nil || tte < selectedTimeToExhaustion ||
+			(tte == selectedTimeToExhaustion && policy.Priority > selected.Priority) {
+			p := policy
+			selected = &p
+			selectedTimeToExhaustion = tte
+		}
+	}
+	if selected == nil {
+		logger.V(logging.DEBUG).Info("Above threshold and beyond horizon for all tuple types", "Horizon", s.Horizon)
+		return nil, nil
+	}
+	return &selected.Type, nil
+}
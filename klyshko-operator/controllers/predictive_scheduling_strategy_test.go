@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package controllers
+
+import (
+	"context"
+	"github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"time"
+)
+
+var _ = Context("PredictiveSchedulingStrategy", func() {
+
+	// Test data
+	strategy := &PredictiveSchedulingStrategy{Horizon: 10 * time.Minute}
+	const TupleTypeA = "A"
+	const TupleTypeB = "B"
+	policies := []v1alpha1.TupleTypePolicy{
+		{
+			Type:      TupleTypeA,
+			Threshold: 1000,
+			Priority:  1,
+		},
+		{
+			Type:      TupleTypeB,
+			Threshold: 1000,
+			Priority:  2,
+		},
+	}
+	var noActiveJobs []v1alpha1.TupleGenerationJob
+
+	Describe("Selecting a tuple type", func() {
+
+		When("all tuple types are above threshold and beyond the horizon", func() {
+			telemetry := castor.Telemetry{
+				TupleMetrics: []castor.TupleMetrics{
+					{Available: 100000, ConsumptionRate: 10, TupleType: TupleTypeA},
+					{Available: 100000, ConsumptionRate: 0, TupleType: TupleTypeB},
+				},
+			}
+			It("returns nil", func() {
+				Expect(strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)).To(BeNil())
+			})
+		})
+
+		When("a tuple type above threshold is expected to run dry within the horizon", func() {
+			telemetry := castor.Telemetry{
+				TupleMetrics: []castor.TupleMetrics{
+					{Available: 50000, ConsumptionRate: 100, TupleType: TupleTypeA},
+					{Available: 100000, ConsumptionRate: 0, TupleType: TupleTypeB},
+				},
+			}
+			It("returns that tuple type", func() {
+				selected, err := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(selected).NotTo(BeNil())
+				Expect(*selected).To(Equal(TupleTypeA))
+			})
+			It("returns nil if in-flight tuples push it beyond the horizon", func() {
+				activeJobs := []v1alpha1.TupleGenerationJob{
+					{Spec: v1alpha1.TupleGenerationJobSpec{Type: TupleTypeA, Count: 100000}},
+				}
+				Expect(strategy.Schedule(context.Background(), telemetry, policies, activeJobs)).To(BeNil())
+			})
+		})
+
+		When("multiple tuple types are eligible", func() {
+			telemetry := castor.Telemetry{
+				TupleMetrics: []castor.TupleMetrics{
+					{Available: 50000, ConsumptionRate: 100, TupleType: TupleTypeA},
+					{Available: 500, ConsumptionRate: 0, TupleType: TupleTypeB},
+				},
+			}
+			It("returns the one closest to running dry", func() {
+				selected, err := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(selected).NotTo(BeNil())
+				Expect(*selected).To(Equal(TupleTypeA))
+			})
+		})
+
+		When("eligible tuple types are expected to run dry at the same time", func() {
+			telemetry := castor.Telemetry{
+				TupleMetrics: []castor.TupleMetrics{
+					{Available: 500, ConsumptionRate: 0, TupleType: TupleTypeA},
+					{Available: 500, ConsumptionRate: 0, TupleType: TupleTypeB},
+				},
+			}
+			It("returns the one with the highest priority", func() {
+				selected, err := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(selected).NotTo(BeNil())
+				Expect(*selected).To(Equal(TupleTypeB))
+			})
+		})
+	})
+
+	Describe("Instantiating the strategy", func() {
+
+		It("uses the default horizon if none is given", func() {
+			s, err := NewPredictiveSchedulingStrategy(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.(*PredictiveSchedulingStrategy).Horizon).To(Equal(defaultPredictiveHorizon))
+		})
+
+		It("uses the given horizon", func() {
+			s, err := NewSchedulingStrategyRegistry().New(v1alpha1.SchedulingStrategySpec{
+				Name:       PredictiveSchedulingStrategyName,
+				Parameters: map[string]string{"horizon": "1h"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.(*PredictiveSchedulingStrategy).Horizon).To(Equal(time.Hour))
+		})
+
+		It("fails for a malformed horizon", func() {
+			_, err := NewPredictiveSchedulingStrategy(map[string]string{"horizon": "soon"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022-2023 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -9,37 +9,127 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"github.com/carbynestack/klyshko/api/v1alpha1"
 	"github.com/carbynestack/klyshko/castor"
 	"github.com/carbynestack/klyshko/logging"
 	"math/rand"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+const (
+	// LotterySchedulingStrategyName is the name under which the LotterySchedulingStrategy is registered.
+	LotterySchedulingStrategyName = "Lottery"
+
+	// lotterySeedParameter is the name of the parameter used to seed the random number generator used by the
+	// LotterySchedulingStrategy.
+	lotterySeedParameter = "seed"
 )
 
 // SchedulingStrategy is used to decide for which tuple type a tuple generation job should be launched next. Note that
-// strategies are instantiated for each scheduling decision.
+// strategies are instantiated once per scheduler and are reused for subsequent scheduling decisions as long as the
+// strategy specification of the scheduler does not change. Implementations may hence keep state across decisions.
 type SchedulingStrategy interface {
 
 	// Schedule returns the tuple type for which tuples should be generated next based on the number of tuples
 	// available in Castor, a set of tuple-specific policies, and the currently running jobs. In case no tuples should
-	// be generated `nil` is returned. Arguments must not be altered by implementations.
-	Schedule(ctx context.Context, telemetry castor.Telemetry, policies []v1alpha1.TupleTypePolicy, activeJobs []v1alpha1.TupleGenerationJob) *string
+	// be generated `nil` is returned. An error is returned in case the policies don't allow for a decision, e.g., as
+	// none of the eligible tuple types has a positive priority. Arguments must not be altered by implementations.
+	Schedule(ctx context.Context, telemetry castor.Telemetry, policies []v1alpha1.TupleTypePolicy, activeJobs []v1alpha1.TupleGenerationJob) (*string, error)
 }
 
-// LotterySchedulingStrategy behaves as if it were selecting the tuple type for which to generate tuples next by a
-// lottery. More specifically, it can be thought of as assigning lottery tickets to each eligible, i.e, below-threshold
-// tuple type, and then selecting a winner by drawing a random ticket.
-type LotterySchedulingStrategy struct {
+// SchedulingStrategyFactory creates a SchedulingStrategy from the given strategy-specific parameters. An error is
+// returned in case the parameters are not supported by the strategy.
+type SchedulingStrategyFactory func(parameters map[string]string) (SchedulingStrategy, error)
+
+// SchedulingStrategyRegistry maps the names used to reference scheduling strategies from TupleGenerationScheduler
+// resources to the factories used to instantiate them.
+type SchedulingStrategyRegistry struct {
+	mutex     sync.RWMutex
+	factories map[string]SchedulingStrategyFactory
 }
 
-// Schedule returns the tuple type selected by this strategy or `nil` if none is eligible.
-func (s *LotterySchedulingStrategy) Schedule(
-	ctx context.Context, telemetry castor.Telemetry, policies []v1alpha1.TupleTypePolicy,
-	activeJobs []v1alpha1.TupleGenerationJob) *string {
+// NewSchedulingStrategyRegistry creates a SchedulingStrategyRegistry with all strategies built into Klyshko being
+// registered.
+func NewSchedulingStrategyRegistry() *SchedulingStrategyRegistry {
+	return &SchedulingStrategyRegistry{
+		factories: map[string]SchedulingStrategyFactory{
+			LotterySchedulingStrategyName:           NewLotterySchedulingStrategy,
+			PredictiveSchedulingStrategyName:        NewPredictiveSchedulingStrategy,
+			DeficitRoundRobinSchedulingStrategyName: NewDeficitRoundRobinSchedulingStrategy,
+		},
+	}
+}
 
-	logger := log.FromContext(ctx)
+// Register makes the strategy created by the given factory available under the given name. Fails in case a strategy
+// with the same name has been registered before.
+func (r *SchedulingStrategyRegistry) Register(name string, factory SchedulingStrategyFactory) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.factories[name]; exists {
+		return fmt.Errorf("scheduling strategy '%s' is registered already", name)
+	}
+	r.factories[name] = factory
+	return nil
+}
 
-	// Compute aggregate of available and in-flight tuples
+// Names returns the lexicographically sorted names of all registered strategies.
+func (r *SchedulingStrategyRegistry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New instantiates the strategy described by the given specification. An empty name refers to the
+// LotterySchedulingStrategy. Fails in case no strategy is registered under the given name or the parameters are not
+// accepted by the strategy.
+func (r *SchedulingStrategyRegistry) New(spec v1alpha1.SchedulingStrategySpec) (SchedulingStrategy, error) {
+	name := spec.Name
+	if name == "" {
+		name = LotterySchedulingStrategyName
+	}
+	r.mutex.RLock()
+	factory, exists := r.factories[name]
+	r.mutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown scheduling strategy '%s' - must be one of %v", name, r.Names())
+	}
+	strategy, err := factory(spec.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for scheduling strategy '%s': %w", name, err)
+	}
+	return strategy, nil
+}
+
+// checkParameters fails in case the given strategy parameters contain a parameter not contained in the given list of
+// supported parameters.
+func checkParameters(parameters map[string]string, supported ...string) error {
+	for name := range parameters {
+		known := false
+		for _, s := range supported {
+			if name == s {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unsupported parameter '%s' - must be one of %v", name, supported)
+		}
+	}
+	return nil
+}
+
+// withInflightTuples returns a copy of the given telemetry where the tuples to be generated by the given jobs are added
+// to the number of available tuples of the respective tuple type.
+func withInflightTuples(telemetry castor.Telemetry, activeJobs []v1alpha1.TupleGenerationJob) castor.Telemetry {
 	t := telemetry.DeepCopy()
 	for _, j := range activeJobs {
 		for idx := range t.TupleMetrics {
@@ -49,6 +139,43 @@ func (s *LotterySchedulingStrategy) Schedule(
 			}
 		}
 	}
+	return t
+}
+
+// LotterySchedulingStrategy behaves as if it were selecting the tuple type for which to generate tuples next by a
+// lottery. More specifically, it can be thought of as assigning lottery tickets to each eligible, i.e, below-threshold
+// tuple type, and then selecting a winner by drawing a random ticket.
+type LotterySchedulingStrategy struct {
+	// random is the source of randomness used to draw tickets. The global source is used in case it is nil.
+	random *rand.Rand
+}
+
+// NewLotterySchedulingStrategy creates a LotterySchedulingStrategy. The optional `seed` parameter can be used to seed
+// the source of randomness to make the sequence of drawn tickets reproducible.
+func NewLotterySchedulingStrategy(parameters map[string]string) (SchedulingStrategy, error) {
+	if err := checkParameters(parameters, lotterySeedParameter); err != nil {
+		return nil, err
+	}
+	strategy := &LotterySchedulingStrategy{}
+	if seedStr, ok := parameters[lotterySeedParameter]; ok {
+		seed, err := strconv.ParseInt(seedStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed '%s' - not an integer: %w", seedStr, err)
+		}
+		strategy.random = rand.New(rand.NewSource(seed))
+	}
+	return strategy, nil
+}
+
+// Schedule returns the tuple type selected by this strategy or `nil` if none is eligible.
+func (s *LotterySchedulingStrategy) Schedule(
+	ctx context.Context, telemetry castor.Telemetry, policies []v1alpha1.TupleTypePolicy,
+	activeJobs []v1alpha1.TupleGenerationJob) (*string, error) {
+
+	logger := log.FromContext(ctx)
+
+	// Compute aggregate of available and in-flight tuples
+	t := withInflightTuples(telemetry, activeJobs)
 	logger.V(logging.DEBUG).Info("With in-flight tuple generation jobs", "Metrics.WithInflight", t.TupleMetrics)
 
 	// Create map of policies by tuple type
@@ -57,14 +184,15 @@ func (s *LotterySchedulingStrategy) Schedule(
 		policyByType[policy.Type] = policy
 	}
 
-	// Filter for those tuple types with a policy that are below their threshold and accumulate all weights
+	// Filter for those tuple types with a policy that are below their threshold and accumulate all weights. Eligible
+	// types are kept in telemetry order to make draws reproducible for seeded strategies.
 	weightSum := 0
-	belowThreshold := map[string]castor.TupleMetrics{}
+	var belowThreshold []string
 	for _, m := range t.TupleMetrics {
 		if policy, exists := policyByType[m.TupleType]; exists {
 			if m.Available < policy.Threshold {
 				weightSum += policy.Priority
-				belowThreshold[m.TupleType] = m
+				belowThreshold = append(belowThreshold, m.TupleType)
 			}
 		}
 	}
@@ -73,14 +201,22 @@ func (s *LotterySchedulingStrategy) Schedule(
 	// Terminate early if no tuple type is below threshold
 	if len(belowThreshold) == 0 {
 		logger.V(logging.DEBUG).Info("Above threshold for all tuple types")
-		return nil
+		return nil, nil
+	}
+	if weightSum <= 0 {
+		return nil, fmt.Errorf("priorities of eligible tuple types %v don't sum up to a positive weight", belowThreshold)
 	}
 
 	// Randomly select a tuple type with probability determined by their individual weight and the sum of all weights
-	r := rand.Intn(weightSum)
+	var r int
+	if s.random != nil {
+		r = s.random.Intn(weightSum)
+	} else {
+		r = rand.Intn(weightSum)
+	}
 	current := 0
 	var selectedTupleType *string = nil
-	for tupleType := range belowThreshold {
+	for _, tupleType := range belowThreshold {
 		policy := policyByType[tupleType]
 		current += policy.Priority
 		if r < current {
@@ -89,5 +225,5 @@ func (s *LotterySchedulingStrategy) Schedule(
 		}
 	}
 
-	return selectedTupleType
+	return selectedTupleType, nil
 }
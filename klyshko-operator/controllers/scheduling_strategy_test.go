@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022-2023 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -58,11 +58,26 @@ var _ = Context("LotterySchedulingStrategy", func() {
 				},
 			}
 			It("returns nil", func() {
-				selected := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				selected, err := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				Expect(err).NotTo(HaveOccurred())
 				Expect(selected).To(BeNil())
 			})
 		})
 
+		When("no tuple type below the threshold has a positive priority", func() {
+			policies := []v1alpha1.TupleTypePolicy{
+				{
+					Type:      TupleTypeA,
+					Threshold: 10000,
+					Priority:  0,
+				},
+			}
+			It("fails", func() {
+				_, err := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
 		When("there is a single tuple type below the threshold", func() {
 			policies := []v1alpha1.TupleTypePolicy{
 				{
@@ -76,7 +91,8 @@ var _ = Context("LotterySchedulingStrategy", func() {
 				},
 			}
 			It("returns that tuple type", func() {
-				selected := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				selected, err := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				Expect(err).NotTo(HaveOccurred())
 				Expect(*selected).To(Equal(TupleTypeB))
 			})
 		})
@@ -94,7 +110,8 @@ var _ = Context("LotterySchedulingStrategy", func() {
 				},
 			}
 			It("returns one of them", func() {
-				selected := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				selected, err := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+				Expect(err).NotTo(HaveOccurred())
 				Expect(*selected).To(BeElementOf(TupleTypeA, TupleTypeB))
 			})
 			It("returns them according to their priorities", func() {
@@ -104,7 +121,8 @@ var _ = Context("LotterySchedulingStrategy", func() {
 				}
 				count := 10000
 				for i := 0; i < count; i++ {
-					selected := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+					selected, err := strategy.Schedule(context.Background(), telemetry, policies, noActiveJobs)
+					Expect(err).NotTo(HaveOccurred())
 					Expect(selected).NotTo(BeNil())
 					counts[*selected]++
 				}
@@ -123,7 +141,8 @@ var _ = Context("LotterySchedulingStrategy", func() {
 				},
 			}
 			It("returns nil", func() {
-				selected := strategy.Schedule(context.Background(), telemetry, policies, someActiveJobs)
+				selected, err := strategy.Schedule(context.Background(), telemetry, policies, someActiveJobs)
+				Expect(err).NotTo(HaveOccurred())
 				Expect(selected).To(BeNil())
 			})
 		})
@@ -137,7 +156,8 @@ var _ = Context("LotterySchedulingStrategy", func() {
 				},
 			}
 			It("returns that tuple type", func() {
-				selected := strategy.Schedule(context.Background(), telemetry, policies, someActiveJobs)
+				selected, err := strategy.Schedule(context.Background(), telemetry, policies, someActiveJobs)
+				Expect(err).NotTo(HaveOccurred())
 				Expect(*selected).To(Equal(TupleTypeA))
 			})
 		})
@@ -145,3 +165,104 @@ var _ = Context("LotterySchedulingStrategy", func() {
 	})
 
 })
+
+var _ = Context("SchedulingStrategyRegistry", func() {
+
+	registry := NewSchedulingStrategyRegistry()
+
+	Describe("Instantiating a strategy", func() {
+
+		When("no name is given", func() {
+			It("returns a lottery strategy", func() {
+				strategy, err := registry.New(v1alpha1.SchedulingStrategySpec{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(strategy).To(BeAssignableToTypeOf(&LotterySchedulingStrategy{}))
+			})
+		})
+
+		When("the name is unknown", func() {
+			It("fails", func() {
+				_, err := registry.New(v1alpha1.SchedulingStrategySpec{Name: "Unknown"})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("an unsupported parameter is given", func() {
+			It("fails", func() {
+				_, err := registry.New(v1alpha1.SchedulingStrategySpec{
+					Name:       LotterySchedulingStrategyName,
+					Parameters: map[string]string{"unknown": "value"},
+				})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("a malformed seed is given for the lottery strategy", func() {
+			It("fails", func() {
+				_, err := registry.New(v1alpha1.SchedulingStrategySpec{
+					Name:       LotterySchedulingStrategyName,
+					Parameters: map[string]string{lotterySeedParameter: "abc"},
+				})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Registering a strategy", func() {
+
+		factory := func(map[string]string) (SchedulingStrategy, error) {
+			return &LotterySchedulingStrategy{}, nil
+		}
+
+		When("the name is not taken", func() {
+			It("makes the strategy available", func() {
+				r := NewSchedulingStrategyRegistry()
+				Expect(r.Register("Custom", factory)).To(Succeed())
+				Expect(r.Names()).To(ContainElement("Custom"))
+				_, err := r.New(v1alpha1.SchedulingStrategySpec{Name: "Custom"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		When("the name is taken", func() {
+			It("fails", func() {
+				r := NewSchedulingStrategyRegistry()
+				Expect(r.Register(LotterySchedulingStrategyName, factory)).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("Using seeded lottery strategies", func() {
+
+		telemetry := castor.Telemetry{
+			TupleMetrics: []castor.TupleMetrics{
+				{Available: 0, TupleType: "A"},
+				{Available: 0, TupleType: "B"},
+				{Available: 0, TupleType: "C"},
+			},
+		}
+		policies := []v1alpha1.TupleTypePolicy{
+			{Type: "A", Threshold: 1, Priority: 1},
+			{Type: "B", Threshold: 1, Priority: 1},
+			{Type: "C", Threshold: 1, Priority: 1},
+		}
+		spec := v1alpha1.SchedulingStrategySpec{
+			Name:       LotterySchedulingStrategyName,
+			Parameters: map[string]string{lotterySeedParameter: "42"},
+		}
+
+		It("draws the same sequence of tuple types for the same seed", func() {
+			first, err := registry.New(spec)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := registry.New(spec)
+			Expect(err).NotTo(HaveOccurred())
+			for i := 0; i < 100; i++ {
+				a, err := first.Schedule(context.Background(), telemetry, policies, nil)
+				Expect(err).NotTo(HaveOccurred())
+				b, err := second.Schedule(context.Background(), telemetry, policies, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*a).To(Equal(*b))
+			}
+		})
+	})
+})
@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	"github.com/carbynestack/klyshko/logging"
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+const (
+	// InitialActivationBackoff is the time to wait before retrying a failed tuple chunk activation for the first time.
+	// The backoff doubles with each subsequent failure.
+	InitialActivationBackoff = 5 * time.Second
+
+	// MaxActivationBackoff is the upper bound for the time to wait before retrying a failed tuple chunk activation.
+	MaxActivationBackoff = 5 * time.Minute
+
+	// MaxActivationAttempts is the number of failed tuple chunk activation attempts after which the job fails.
+	MaxActivationAttempts = 10
+)
+
+// allCompleted checks whether the given tasks of a job completed on all of the given number of VCPs.
+func allCompleted(tasks []klyshkov1alpha1.TupleGenerationTask, numberOfVCPs uint) bool {
+	if uint(len(tasks)) < numberOfVCPs {
+		return false
+	}
+	for _, t := range tasks {
+		if t.Status.State != klyshkov1alpha1.TaskCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// getActivationBackoff returns the time to wait before retrying a tuple chunk activation after the given number of
+// failed attempts.
+func getActivationBackoff(attempts int) time.Duration {
+	backoff := InitialActivationBackoff
+	for i := 1; i < attempts && backoff < MaxActivationBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxActivationBackoff {
+		backoff = MaxActivationBackoff
+	}
+	return backoff
+}
+
+// activationExhausted checks whether the activation of the tuple chunk generated by the given job failed for the
+// maximum number of attempts, such that it is not retried anymore.
+func activationExhausted(job klyshkov1alpha1.TupleGenerationJob) bool {
+	activation := job.Status.Activation
+	return activation != nil && !activation.Activated && activation.Attempts >= MaxActivationAttempts
+}
+
+// appliesVerdict checks whether the verdict recorded in the given roster, if any, applies to the given job, i.e.,
+// whether the tuple chunk generated by the job has not been activated locally yet.
+func appliesVerdict(job klyshkov1alpha1.TupleGenerationJob, roster rosterJob) bool {
+	activation := job.Status.Activation
+	return roster.hasVerdict() && (activation == nil || !activation.Activated)
+}
+
+// recordActivationInRoster records in the roster of the job with the given key that the VCP of the given player
+// activates the tuple chunk generated by the job, unless recorded already. The roster is updated only in case it has
+// not been modified since the given revision, such that the activation is never recorded along with a verdict.
+func (r *TupleGenerationJobReconciler) recordActivationInRoster(ctx context.Context, key RosterKey, revision int64, roster *rosterJob, playerID uint) error {
+	for _, p := range roster.Activated {
+		if p == playerID {
+			return nil
+		}
+	}
+	roster.Activated = append(roster.Activated, playerID)
+	if err := r.updateRoster(ctx, key, revision, roster); err != nil {
+		return fmt.Errorf("failed to record activation in roster for job %v: %w", key.Name, err)
+	}
+	return nil
+}
+
+// recordActivation records the outcome of an attempt to activate the tuple chunk generated by the given job made at the
+// given point in time in the status of the job. The given error is nil in case the activation succeeded.
+func recordActivation(job *klyshkov1alpha1.TupleGenerationJob, err error, now metav1.Time) {
+	activation := job.Status.Activation
+	if activation == nil {
+		activation = &klyshkov1alpha1.TupleChunkActivationStatus{}
+		job.Status.Activation = activation
+	}
+	activation.Time = now
+	activation.Attempts++
+	if err != nil && activation.Attempts >= MaxActivationAttempts {
+		activation.NextAttemptTime = nil
+		activation.Error = err.Error()
+		setJobCondition(job, klyshkov1alpha1.JobTupleChunkActivated, metav1.ConditionFalse, "ActivationFailed",
+			fmt.Sprintf("Activation attempt %d failed, giving up: %v", activation.Attempts, err))
+		return
+	}
+	if err != nil {
+		backoff := getActivationBackoff(activation.Attempts)
+		next := metav1.NewTime(now.Add(backoff))
+		activation.NextAttemptTime = &next
+		activation.Error = err.Error()
+		setJobCondition(job, klyshkov1alpha1.JobTupleChunkActivated, metav1.ConditionFalse, "ActivationFailed",
+			fmt.Sprintf("Activation attempt %d failed, retrying in %v: %v", activation.Attempts, backoff, err))
+		return
+	}
+	activation.Activated = true
+	activation.NextAttemptTime = nil
+	activation.Error = ""
+	setJobCondition(job, klyshkov1alpha1.JobTupleChunkActivated, metav1.ConditionTrue, "Activated",
+		fmt.Sprintf("Tuple chunk %s activated", job.Spec.ID))
+}
+
+// activateTupleChunk activates the tuple chunk generated by the given job in Castor and records the outcome in the
+// status of the job, unless the tuple chunk has been activated before. In case the activation failed, the backoff
+// until the next attempt is returned, which is zero in case the maximum number of attempts has been reached. The
+// activation status of the tuple chunk is queried from Castor beforehand as a hint only, i.e., the tuple chunk is
+// activated in case the status can't be determined. Castor reporting the tuple chunk to be activated already when
+// activating it is considered a success as well, such that tuple chunks are activated exactly once, even if recording
+// a previous successful activation failed.
+func (r *TupleGenerationJobReconciler) activateTupleChunk(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob) (bool, time.Duration, error) {
+	activation := job.Status.Activation
+	if activation != nil && activation.Activated {
+		return true, 0, nil
+	}
+	if activationExhausted(*job) {
+		return false, 0, nil
+	}
+	if activation != nil && activation.NextAttemptTime != nil {
+		if wait := time.Until(activation.NextAttemptTime.Time); wait > 0 {
+			return false, wait, nil
+		}
+	}
+	tupleChunkID, err := uuid.Parse(job.Spec.ID)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid job id encountered '%v': %w", job.Spec.ID, err)
+	}
+	activated, err := r.CastorClient.IsTupleChunkActivated(ctx, tupleChunkID)
+	if err != nil {
+		r.Logger.V(logging.DEBUG).Info("Tuple chunk status not available", "Job.Name", job.Name,
+			"TupleChunkId", tupleChunkID, "Error", err.Error())
+	}
+	if activated {
+		r.Logger.Info("Tuple chunk activated already", "Job.Name", job.Name, "TupleChunkId", tupleChunkID)
+		err = nil
+	} else {
+		err = r.CastorClient.ActivateTupleChunk(ctx, tupleChunkID)
+		if errors.Is(err, castor.ErrTupleChunkAlreadyActivated) {
+			r.Logger.Info("Tuple chunk activated already", "Job.Name", job.Name, "TupleChunkId", tupleChunkID)
+			err = nil
+		}
+	}
+	recordActivation(job, err, metav1.Now())
+	if err != nil {
+		r.Logger.Error(err, "Tuple chunk activation failed", "Job.Name", job.Name,
+			"Attempt", job.Status.Activation.Attempts)
+		if job.Status.Activation.NextAttemptTime == nil {
+			return false, 0, nil
+		}
+		return false, time.Until(job.Status.Activation.NextAttemptTime.Time), nil
+	}
+	return true, 0, nil
+}
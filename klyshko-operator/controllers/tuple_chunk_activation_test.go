@@ -0,0 +1,212 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	"github.com/go-logr/logr"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+var _ = Describe("Activating tuple chunks", func() {
+	const castorURL = "http://castor.default.svc.cluster.local:10100"
+	const chunkID = "14d8d5e2-2d3c-4b0e-8a6f-1b2f0f0e5a11"
+	now := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	newJob := func() *klyshkov1alpha1.TupleGenerationJob {
+		job := newTestJob(newTestScheduler("A"), "job", "A", klyshkov1alpha1.JobRunning)
+		job.Spec.ID = chunkID
+		return job
+	}
+	activateURL := fmt.Sprintf("%s/intra-vcp/tuple-chunks/activate/%s", castorURL, chunkID)
+	statusURL := fmt.Sprintf("%s/intra-vcp/tuple-chunks/%s/status", castorURL, chunkID)
+	respondWith := func(chunkStatus string, status int) {
+		httpmock.RegisterResponder("GET", statusURL,
+			httpmock.NewStringResponder(200, fmt.Sprintf(`{"chunkId":"%s","status":"%s"}`, chunkID, chunkStatus)))
+		httpmock.RegisterResponder("PUT", activateURL, httpmock.NewStringResponder(status, ""))
+	}
+	reconciler := func() *TupleGenerationJobReconciler {
+		return &TupleGenerationJobReconciler{CastorClient: castor.NewClient(castorURL), Logger: logr.Discard()}
+	}
+
+	BeforeEach(func() {
+		httpmock.Activate()
+	})
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	It("requires the tasks to be completed on all VCPs", func() {
+		completed := klyshkov1alpha1.TupleGenerationTask{
+			Status: klyshkov1alpha1.TupleGenerationTaskStatus{State: klyshkov1alpha1.TaskCompleted},
+		}
+		failed := klyshkov1alpha1.TupleGenerationTask{
+			Status: klyshkov1alpha1.TupleGenerationTaskStatus{State: klyshkov1alpha1.TaskFailed},
+		}
+		Expect(allCompleted([]klyshkov1alpha1.TupleGenerationTask{completed, completed}, 2)).To(BeTrue())
+		Expect(allCompleted([]klyshkov1alpha1.TupleGenerationTask{completed}, 2)).To(BeFalse())
+		Expect(allCompleted([]klyshkov1alpha1.TupleGenerationTask{completed, failed}, 2)).To(BeFalse())
+	})
+
+	It("doubles the backoff with each failed attempt up to the maximum", func() {
+		Expect(getActivationBackoff(1)).To(Equal(InitialActivationBackoff))
+		Expect(getActivationBackoff(2)).To(Equal(2 * InitialActivationBackoff))
+		Expect(getActivationBackoff(100)).To(Equal(MaxActivationBackoff))
+	})
+
+	It("records failed and successful attempts", func() {
+		job := newJob()
+		recordActivation(job, errors.New("castor unavailable"), now)
+		Expect(job.Status.Activation.Activated).To(BeFalse())
+		Expect(job.Status.Activation.Attempts).To(Equal(1))
+		Expect(job.Status.Activation.NextAttemptTime.Time).To(Equal(now.Add(InitialActivationBackoff)))
+		Expect(job.Status.Activation.Error).To(Equal("castor unavailable"))
+		condition := meta.FindStatusCondition(job.Status.Conditions, klyshkov1alpha1.JobTupleChunkActivated)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+
+		recordActivation(job, nil, now)
+		Expect(job.Status.Activation.Activated).To(BeTrue())
+		Expect(job.Status.Activation.Attempts).To(Equal(2))
+		Expect(job.Status.Activation.NextAttemptTime).To(BeNil())
+		Expect(job.Status.Activation.Error).To(BeEmpty())
+		condition = meta.FindStatusCondition(job.Status.Conditions, klyshkov1alpha1.JobTupleChunkActivated)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("gives up after the maximum number of failed attempts", func() {
+		job := newJob()
+		for i := 1; i < MaxActivationAttempts; i++ {
+			recordActivation(job, errors.New("castor unavailable"), now)
+			Expect(activationExhausted(*job)).To(BeFalse())
+		}
+		recordActivation(job, errors.New("castor unavailable"), now)
+		Expect(activationExhausted(*job)).To(BeTrue())
+		Expect(job.Status.Activation.NextAttemptTime).To(BeNil())
+	})
+
+	It("backs off after a failed activation", func() {
+		respondWith("LOCKED", 500)
+		job := newJob()
+		activated, backoff, err := reconciler().activateTupleChunk(context.Background(), job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(activated).To(BeFalse())
+		Expect(backoff).To(BeNumerically("~", InitialActivationBackoff, time.Second))
+
+		activated, _, err = reconciler().activateTupleChunk(context.Background(), job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(activated).To(BeFalse())
+		Expect(httpmock.GetCallCountInfo()["PUT "+activateURL]).To(Equal(1))
+	})
+
+	It("doesn't retry activations after the maximum number of failed attempts", func() {
+		respondWith("LOCKED", 500)
+		job := newJob()
+		job.Status.Activation = &klyshkov1alpha1.TupleChunkActivationStatus{Attempts: MaxActivationAttempts - 1}
+		activated, backoff, err := reconciler().activateTupleChunk(context.Background(), job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(activated).To(BeFalse())
+		Expect(backoff).To(BeZero())
+		Expect(activationExhausted(*job)).To(BeTrue())
+
+		_, _, err = reconciler().activateTupleChunk(context.Background(), job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(httpmock.GetTotalCallCount()).To(Equal(2))
+	})
+
+	It("doesn't activate tuple chunks Castor reports to be activated already", func() {
+		respondWith("UNLOCKED", 500)
+		job := newJob()
+		activated, _, err := reconciler().activateTupleChunk(context.Background(), job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(activated).To(BeTrue())
+		Expect(job.Status.Activation.Activated).To(BeTrue())
+		Expect(httpmock.GetCallCountInfo()["PUT "+activateURL]).To(BeZero())
+	})
+
+	It("considers tuple chunks Castor reports a conflict for when activating them to be activated", func() {
+		respondWith("LOCKED", 409)
+		job := newJob()
+		activated, _, err := reconciler().activateTupleChunk(context.Background(), job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(activated).To(BeTrue())
+		Expect(job.Status.Activation.Activated).To(BeTrue())
+	})
+
+	It("activates tuple chunks in case their status is not available", func() {
+		for _, status := range []int{404, 500} {
+			httpmock.Reset()
+			httpmock.RegisterResponder("GET", statusURL, httpmock.NewStringResponder(status, ""))
+			httpmock.RegisterResponder("PUT", activateURL, httpmock.NewStringResponder(200, ""))
+			job := newJob()
+			activated, _, err := reconciler().activateTupleChunk(context.Background(), job)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(activated).To(BeTrue())
+			Expect(httpmock.GetCallCountInfo()["PUT "+activateURL]).To(Equal(1))
+		}
+	})
+
+	It("applies verdicts as long as the tuple chunk has not been activated locally", func() {
+		job := newJob()
+		Expect(appliesVerdict(*job, rosterJob{})).To(BeFalse())
+		Expect(appliesVerdict(*job, rosterJob{Cancelled: true})).To(BeTrue())
+		recordActivation(job, errors.New("unavailable"), now)
+		Expect(appliesVerdict(*job, rosterJob{Cancelled: true})).To(BeTrue())
+		recordActivation(job, nil, now)
+		Expect(appliesVerdict(*job, rosterJob{Cancelled: true})).To(BeFalse())
+	})
+
+	It("stores the VCPs activating the tuple chunk in the roster", func() {
+		roster := rosterJob{}
+		Expect(roster.hasActivations()).To(BeFalse())
+		encoded, err := json.Marshal(rosterJob{Activated: []uint{1, 0}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(json.Unmarshal(encoded, &roster)).To(Succeed())
+		Expect(roster.Activated).To(Equal([]uint{1, 0}))
+		Expect(roster.hasActivations()).To(BeTrue())
+	})
+
+	It("cancels jobs whose tasks completed unless the tuple chunk has been activated locally", func() {
+		scheduler := newTestScheduler("A")
+		pending := newTestJob(scheduler, "pending", "A", klyshkov1alpha1.JobRunning)
+		activated := newTestJob(scheduler, "activated", "A", klyshkov1alpha1.JobRunning)
+		recordActivation(activated, nil, now)
+		r := &TupleGenerationJobReconciler{Client: newFakeSchedulerReconciler(nil, pending, activated).Client}
+
+		for _, name := range []string{"pending", "activated"} {
+			Expect(r.applyRosterVerdict(context.Background(),
+				types.NamespacedName{Namespace: "default", Name: name}, rosterJob{Cancelled: true})).To(Succeed())
+		}
+		job := &klyshkov1alpha1.TupleGenerationJob{}
+		Expect(r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "pending"}, job)).To(Succeed())
+		Expect(job.Status.State).To(Equal(klyshkov1alpha1.JobCancelled))
+		job = &klyshkov1alpha1.TupleGenerationJob{}
+		Expect(r.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "activated"}, job)).To(Succeed())
+		Expect(job.Status.State).To(Equal(klyshkov1alpha1.JobRunning))
+	})
+
+	It("activates tuple chunks only once", func() {
+		respondWith("LOCKED", 200)
+		job := newJob()
+		for i := 0; i < 2; i++ {
+			activated, _, err := reconciler().activateTupleChunk(context.Background(), job)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(activated).To(BeTrue())
+		}
+		Expect(httpmock.GetTotalCallCount()).To(Equal(2))
+	})
+})
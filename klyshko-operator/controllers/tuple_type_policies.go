@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+)
+
+// ApplyTupleTypePolicies derives the policies handed over to the scheduling strategy from the given serviceable
+// policies by applying fill targets and per tuple type concurrency limits.
+//
+// A tuple type starts filling as soon as the number of available and in-flight tuples drops below its threshold and
+// keeps filling until the target is reached. While filling, the threshold of the policy is raised to the target, such
+// that strategies keep selecting the tuple type instead of oscillating around the threshold. Whether a tuple type was
+// filling before is taken from the given previously observed tuple type states.
+//
+// Policies for tuple types that reached their maximum number of concurrent jobs are removed. In case the window of the
+// scheduler is closed, only the policies for tuple types that dropped below their emergency threshold are retained.
+//
+// The given telemetry is expected to include in-flight tuples already. Returns the effective policies along with the
+// observed state per tuple type.
+func ApplyTupleTypePolicies(policies []klyshkov1alpha1.TupleTypePolicy, telemetry castor.Telemetry,
+	activeJobs []klyshkov1alpha1.TupleGenerationJob, previous []klyshkov1alpha1.TupleTypeStatus, windowOpen bool) ([]klyshkov1alpha1.TupleTypePolicy, []klyshkov1alpha1.TupleTypeStatus) {
+	wasFilling := map[string]bool{}
+	for _, s := range previous {
+		wasFilling[s.Type] = s.Filling
+	}
+	activeJobsByType := map[string]int{}
+	for _, job := range activeJobs {
+		activeJobsByType[job.Spec.Type]++
+	}
+	var effective []klyshkov1alpha1.TupleTypePolicy
+	var states []klyshkov1alpha1.TupleTypeStatus
+	for _, policy := range policies {
+		available := getAvailableTuples(telemetry, policy.Type)
+		target := policy.Target
+		if target < policy.Threshold {
+			target = policy.Threshold
+		}
+		state := klyshkov1alpha1.TupleTypeStatus{
+			Type:       policy.Type,
+			ActiveJobs: activeJobsByType[policy.Type],
+			Filling:    available < policy.Threshold || (wasFilling[policy.Type] && available < target),
+		}
+		state.Emergency = available < policy.EmergencyThreshold
+		state.AtConcurrencyLimit = policy.MaxConcurrentJobs > 0 && state.ActiveJobs >= policy.MaxConcurrentJobs
+		states = append(states, state)
+		if state.AtConcurrencyLimit || (!windowOpen && !state.Emergency) {
+			continue
+		}
+		if state.Filling {
+			policy.Threshold = target
+		}
+		effective = append(effective, policy)
+	}
+	return effective, states
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Applying tuple type policies", func() {
+	const TupleType = "A"
+	policy := klyshkov1alpha1.TupleTypePolicy{Type: TupleType, Threshold: 1000, Target: 5000, Priority: 1}
+	telemetryWith := func(available int) castor.Telemetry {
+		return castor.Telemetry{TupleMetrics: []castor.TupleMetrics{{TupleType: TupleType, Available: available}}}
+	}
+	activeJob := klyshkov1alpha1.TupleGenerationJob{Spec: klyshkov1alpha1.TupleGenerationJobSpec{Type: TupleType}}
+
+	When("the tuple type drops below its threshold", func() {
+		It("starts filling towards the target", func() {
+			policies, states := ApplyTupleTypePolicies(
+				[]klyshkov1alpha1.TupleTypePolicy{policy}, telemetryWith(500), nil, nil, true)
+			Expect(policies).To(HaveLen(1))
+			Expect(policies[0].Threshold).To(Equal(5000))
+			Expect(states).To(Equal([]klyshkov1alpha1.TupleTypeStatus{{Type: TupleType, Filling: true}}))
+		})
+	})
+
+	When("the tuple type is between threshold and target", func() {
+		It("keeps filling in case it was filling before", func() {
+			policies, states := ApplyTupleTypePolicies([]klyshkov1alpha1.TupleTypePolicy{policy}, telemetryWith(3000),
+				nil, []klyshkov1alpha1.TupleTypeStatus{{Type: TupleType, Filling: true}}, true)
+			Expect(policies[0].Threshold).To(Equal(5000))
+			Expect(states[0].Filling).To(BeTrue())
+		})
+		It("does not start filling otherwise", func() {
+			policies, states := ApplyTupleTypePolicies(
+				[]klyshkov1alpha1.TupleTypePolicy{policy}, telemetryWith(3000), nil, nil, true)
+			Expect(policies[0].Threshold).To(Equal(1000))
+			Expect(states[0].Filling).To(BeFalse())
+		})
+	})
+
+	When("the tuple type reaches its target", func() {
+		It("stops filling", func() {
+			policies, states := ApplyTupleTypePolicies([]klyshkov1alpha1.TupleTypePolicy{policy}, telemetryWith(5000),
+				nil, []klyshkov1alpha1.TupleTypeStatus{{Type: TupleType, Filling: true}}, true)
+			Expect(policies[0].Threshold).To(Equal(1000))
+			Expect(states[0].Filling).To(BeFalse())
+		})
+	})
+
+	When("the window of the scheduler is closed", func() {
+		emergency := policy
+		emergency.EmergencyThreshold = 100
+		It("removes the policy", func() {
+			policies, states := ApplyTupleTypePolicies(
+				[]klyshkov1alpha1.TupleTypePolicy{emergency}, telemetryWith(500), nil, nil, false)
+			Expect(policies).To(BeEmpty())
+			Expect(states[0].Emergency).To(BeFalse())
+		})
+		It("retains the policy in case of an emergency", func() {
+			policies, states := ApplyTupleTypePolicies(
+				[]klyshkov1alpha1.TupleTypePolicy{emergency}, telemetryWith(50), nil, nil, false)
+			Expect(policies).To(HaveLen(1))
+			Expect(states[0].Emergency).To(BeTrue())
+		})
+	})
+
+	When("the tuple type reaches its maximum number of concurrent jobs", func() {
+		It("removes the policy", func() {
+			limited := policy
+			limited.MaxConcurrentJobs = 2
+			policies, states := ApplyTupleTypePolicies([]klyshkov1alpha1.TupleTypePolicy{limited}, telemetryWith(0),
+				[]klyshkov1alpha1.TupleGenerationJob{activeJob, activeJob}, nil, true)
+			Expect(policies).To(BeEmpty())
+			Expect(states[0].ActiveJobs).To(Equal(2))
+			Expect(states[0].AtConcurrencyLimit).To(BeTrue())
+		})
+	})
+})
@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022-2023 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -17,9 +17,9 @@ import (
 	"github.com/carbynestack/klyshko/castor"
 	"github.com/carbynestack/klyshko/logging"
 	"github.com/go-logr/logr"
-	"github.com/google/uuid"
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -35,6 +35,13 @@ const (
 
 	// headRevisionOpRetryPeriod defines the duration between two attempts to store or fetch the head revision in etcd.
 	headRevisionOpRetryPeriod = 5 * time.Second
+
+	// maxEventReplays is the number of times the events of a revision that failed to be processed are replayed before
+	// they are skipped.
+	maxEventReplays = 10
+
+	// MirroredJobLabel marks jobs created by the operator from the roster of a job created on the coordinating VCP.
+	MirroredJobLabel = "klyshko.carbnyestack.io/mirrored"
 )
 
 // TupleGenerationJobReconciler reconciles a TupleGenerationJob object.
@@ -44,16 +51,26 @@ type TupleGenerationJobReconciler struct {
 	EtcdClient   *clientv3.Client
 	CastorClient *castor.Client
 	Logger       logr.Logger
+
+	// APIReader reads directly from the API server, bypassing the informer cache, such that admission decisions are
+	// based on the latest state of all jobs.
+	APIReader client.Reader
+
+	// MaxConcurrentJobs is the maximum number of jobs admitted concurrently, where zero means unlimited. Jobs beyond the
+	// limit are queued until capacity frees up.
+	MaxConcurrentJobs int
 }
 
 // NewTupleGenerationJobReconciler creates a TupleGenerationJobReconciler.
-func NewTupleGenerationJobReconciler(client client.Client, scheme *runtime.Scheme, etcdClient *clientv3.Client, castorClient *castor.Client, logger logr.Logger) *TupleGenerationJobReconciler {
+func NewTupleGenerationJobReconciler(client client.Client, apiReader client.Reader, scheme *runtime.Scheme, etcdClient *clientv3.Client, castorClient *castor.Client, logger logr.Logger, maxConcurrentJobs int) *TupleGenerationJobReconciler {
 	r := &TupleGenerationJobReconciler{
-		Client:       client,
-		Scheme:       scheme,
-		EtcdClient:   etcdClient,
-		CastorClient: castorClient,
-		Logger:       logger,
+		Client:            client,
+		APIReader:         apiReader,
+		Scheme:            scheme,
+		EtcdClient:        etcdClient,
+		CastorClient:      castorClient,
+		Logger:            logger,
+		MaxConcurrentJobs: maxConcurrentJobs,
 	}
 	go r.handleWatchEvents()
 	return r
@@ -106,22 +123,95 @@ func (r *TupleGenerationJobReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to read resource for roster with key %v for task %v: %w", jobKey, req.Name, err)
 	}
+	roster := rosterJob{}
+	var rosterRevision int64
 	if resp.Count == 0 {
 		if playerID != 0 {
 			logger.V(logging.DEBUG).Info("Roster not available, retrying later")
 			return ctrl.Result{}, nil
 		}
-		encoded, err := json.Marshal(job.Spec)
+		roster.TupleGenerationJobSpec = job.Spec
+		encoded, err := json.Marshal(roster)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to marshal specification for job %v: %w", req.Name, err)
 		}
-		_, err = r.EtcdClient.Put(ctx, jobKey.ToEtcdKey(), string(encoded))
+		put, err := r.EtcdClient.Put(ctx, jobKey.ToEtcdKey(), string(encoded))
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to create roster for job %v: %w", req.Name, err)
 		}
+		rosterRevision = put.Header.Revision
 		logger.V(logging.DEBUG).Info("Roster created")
 	} else {
 		logger.V(logging.DEBUG).Info("Roster exists already")
+		if err := json.Unmarshal(resp.Kvs[0].Value, &roster); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to unmarshal roster for job %v: %w", req.Name, err)
+		}
+		rosterRevision = resp.Kvs[0].ModRevision
+	}
+
+	// Record the requested cancellation in the roster, such that the job is cancelled on all VCPs. Jobs that are done,
+	// have been failed by the coordinator, or whose tuple chunk is being activated already can't be cancelled anymore.
+	if job.Spec.Cancel && !roster.Cancelled && roster.Failure == nil && !roster.hasActivations() &&
+		!job.Status.State.IsDone() {
+		if err := r.cancelJobInRoster(ctx, jobKey, rosterRevision, &roster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Fail the job on all VCPs by recording the verdict in the roster in case the deadline has been exceeded, iff we are
+	// the coordinator
+	var untilDeadline time.Duration
+	if playerID == coordinatorPlayerID && !roster.hasVerdict() && !roster.hasActivations() && !job.Status.State.IsDone() {
+		if deadline, ok := getJobDeadline(*job); ok {
+			untilDeadline = time.Until(deadline)
+			if untilDeadline <= 0 {
+				err := r.failJobInRoster(ctx, jobKey, rosterRevision, &roster, klyshkov1alpha1.JobDeadlineExceeded,
+					fmt.Sprintf("Job did not finish within %d seconds", *job.Spec.ActiveDeadlineSeconds))
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+		}
+	}
+
+	// Queued jobs don't spawn tasks until they have been admitted by the coordinator. Jobs that are done without having
+	// been admitted, e.g., as they have been cancelled while being queued, are retried only.
+	if !isAdmitted(*job, roster) {
+		if roster.hasVerdict() || job.Status.State.IsDone() {
+			if err := r.applyRosterVerdict(ctx, req.NamespacedName, roster); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := r.Get(ctx, req.NamespacedName, job); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to read resource for job %v: %w", req.Name, err)
+			}
+			if playerID == coordinatorPlayerID && job.Status.State == klyshkov1alpha1.JobFailed {
+				return r.retryJob(ctx, job)
+			}
+			return ctrl.Result{}, nil
+		}
+		admitted := false
+		if playerID == coordinatorPlayerID {
+			admitted, err = r.admitJob(ctx, jobKey, rosterRevision, &roster, job)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if !admitted {
+			if err := r.queueJob(ctx, job); err != nil {
+				return ctrl.Result{}, err
+			}
+			// Remote VCPs start the job as soon as the coordinator records the admission in the roster. The deadline
+			// of queued jobs doesn't run until they have been admitted.
+			if playerID != coordinatorPlayerID {
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{RequeueAfter: admissionRetryPeriod}, nil
+		}
+	}
+
+	// Sharded jobs are generated by their shards rather than by tasks of their own
+	if isSharded(*job) {
+		return r.reconcileShardedJob(ctx, job, roster, playerID, untilDeadline)
 	}
 
 	// Create local task if not existing
@@ -149,34 +239,17 @@ func (r *TupleGenerationJobReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 	logger.V(logging.DEBUG).Info("Local task exists already", "Task.Name", task.Name)
 
-	// Update job status based on owned task statuses; TODO That might not scale well in case we have many jobs
-	tasks := &klyshkov1alpha1.TupleGenerationTaskList{}
-	err = r.List(ctx, tasks)
+	// Update job status based on owned task statuses
+	ownedBy, err := r.ownedTasks(ctx, job)
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to fetch task list: %w", err)
-	}
-
-	// Collecting tasks owned by job; TODO Consider moving this to task class
-	isOwnedBy := func(task klyshkov1alpha1.TupleGenerationTask) bool {
-		for _, or := range task.OwnerReferences {
-			if or.Name == job.Name {
-				return true
-			}
-		}
-		return false
-	}
-	var ownedBy []klyshkov1alpha1.TupleGenerationTask
-	for _, t := range tasks.Items {
-		if isOwnedBy(t) {
-			ownedBy = append(ownedBy, t)
-		}
+		return ctrl.Result{}, err
 	}
 	logger.V(logging.DEBUG).Info("Collected statuses of owned tasks", "Tasks", ownedBy)
 
 	// Helper functions; TODO Consider moving this to state class
 	allTerminated := func(tasks []klyshkov1alpha1.TupleGenerationTask) bool {
 		for _, t := range tasks {
-			if t.Status.State != klyshkov1alpha1.TaskCompleted && t.Status.State != klyshkov1alpha1.TaskFailed {
+			if !t.Status.State.IsDone() {
 				return false
 			}
 		}
@@ -194,41 +267,106 @@ func (r *TupleGenerationJobReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if err != nil {
 		return ctrl.Result{RequeueAfter: 60 * time.Second}, fmt.Errorf("can't read playerCount from VCP configuration: %w", err)
 	}
+	// Verdicts are applied unless the tuple chunk has been activated locally already. As VCPs record themselves in the
+	// roster before activating the tuple chunk and verdicts are not recorded anymore afterwards, all VCPs agree on
+	// whether the job is cancelled or failed, or the tuple chunk is activated.
+	status := job.Status.DeepCopy()
 	var state klyshkov1alpha1.TupleGenerationJobState
-	if uint(len(ownedBy)) < numberOfVCPs {
+	var reason, message string
+	var activationBackoff time.Duration
+	if appliesVerdict(*job, roster) {
+		state, reason, message = roster.verdict()
+	} else if uint(len(ownedBy)) < numberOfVCPs {
 		state = klyshkov1alpha1.JobPending
 	} else if !allTerminated(ownedBy) {
 		state = klyshkov1alpha1.JobRunning
 	} else if anyFailed(ownedBy) {
 		state = klyshkov1alpha1.JobFailed
+		reason, message = klyshkov1alpha1.JobTaskFailed, describeTaskFailures(ownedBy)
 	} else if job.Status.State != klyshkov1alpha1.JobCompleted {
-		state = klyshkov1alpha1.JobCompleted
-
-		// Activate tuples; TODO How to deal with failures here? Introduce JobActivating state, how to sync between VCPs?
-		tupleChunkID, err := uuid.Parse(job.Spec.ID)
-		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("invalid job id encountered '%v': %w", job.Spec.ID, err)
+		// The job completes as soon as the tuple chunk has been activated, retrying with backoff until it is, and
+		// fails in case the activation failed for the maximum number of attempts
+		if err := r.recordActivationInRoster(ctx, jobKey, rosterRevision, &roster, playerID); err != nil {
+			return ctrl.Result{}, err
 		}
-		err = r.CastorClient.ActivateTupleChunk(ctx, tupleChunkID)
+		activated, backoff, err := r.activateTupleChunk(ctx, job)
 		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("tuple chunk activation failed for job %v: %w", job.Name, err)
+			return ctrl.Result{}, err
+		}
+		if activated {
+			state = klyshkov1alpha1.JobCompleted
+			logger.Info("Job done", "Job", job)
+		} else if activationExhausted(*job) {
+			state = klyshkov1alpha1.JobFailed
+			reason, message = klyshkov1alpha1.JobActivationFailed, fmt.Sprintf(
+				"Tuple chunk activation failed %d times: %s", job.Status.Activation.Attempts, job.Status.Activation.Error)
+		} else {
+			state = klyshkov1alpha1.JobRunning
+			activationBackoff = backoff
 		}
-		logger.Info("Job done", "Job", job)
 	}
 	if state.IsValid() && state != job.Status.State {
 		logger.V(logging.DEBUG).Info("State update", "from", job.Status.State, "to", state)
 		job.Status.State = state
 		job.Status.LastStateTransitionTime = metav1.Now()
+		job.Status.Reason, job.Status.Message = reason, message
+	}
+	updateJobStatus(job, ownedBy, numberOfVCPs)
+
+	// Link retries to the job they retry as agreed on in the roster
+	linked := job.Status.RetryOf != roster.RetryOf || job.Status.Attempt != roster.Attempt
+	if linked {
+		job.Status.RetryOf = roster.RetryOf
+		job.Status.Attempt = roster.Attempt
+	}
+	if !equality.Semantic.DeepEqual(*status, job.Status) {
 		err = r.Status().Update(ctx, job)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("status update failed for job %v: %w", job.Name, err)
 		}
 	}
+	if linked && job.Status.RetryOf != "" {
+		if err := r.linkRetriedJob(ctx, job); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Retry failed jobs, iff we are the coordinator
+	if playerID == coordinatorPlayerID && job.Status.State == klyshkov1alpha1.JobFailed {
+		return r.retryJob(ctx, job)
+	}
 
 	logger.V(logging.DEBUG).Info("Desired state reached")
+	requeueAfter := untilDeadline
+	if activationBackoff > 0 && (requeueAfter <= 0 || activationBackoff < requeueAfter) {
+		requeueAfter = activationBackoff
+	}
+	if requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+// ownedTasks returns the local and remote tasks owned by the given job; TODO That might not scale well in case we have
+// many jobs
+func (r *TupleGenerationJobReconciler) ownedTasks(ctx context.Context, job *klyshkov1alpha1.TupleGenerationJob) ([]klyshkov1alpha1.TupleGenerationTask, error) {
+	tasks := &klyshkov1alpha1.TupleGenerationTaskList{}
+	err := r.List(ctx, tasks, client.InNamespace(job.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch task list: %w", err)
+	}
+	var ownedBy []klyshkov1alpha1.TupleGenerationTask
+	for _, t := range tasks.Items {
+		for _, or := range t.OwnerReferences {
+			if or.Name == job.Name {
+				ownedBy = append(ownedBy, t)
+				break
+			}
+		}
+	}
+	return ownedBy, nil
+}
+
 // taskForJob assembles the TupleGenerationJob resource description for the given job and VCP.
 func (r *TupleGenerationJobReconciler) taskForJob(job *klyshkov1alpha1.TupleGenerationJob, playerID uint) (*klyshkov1alpha1.TupleGenerationTask, error) {
 	task := &klyshkov1alpha1.TupleGenerationTask{
@@ -295,6 +433,7 @@ func (r *TupleGenerationJobReconciler) setHeadRevision(ctx context.Context, name
 // handleWatchEvents handles incoming etcd events and dispatches them individually to handleWatchEvent.
 func (r *TupleGenerationJobReconciler) handleWatchEvents() {
 
+	replays := map[int64]int{}
 	for {
 		ctx, cancel := context.WithCancel(context.Background())
 		retrySleep := func(err error) {
@@ -323,25 +462,54 @@ func (r *TupleGenerationJobReconciler) handleWatchEvents() {
 				cancel()
 				break
 			}
-			for _, ev := range watchResponse.Events {
-				r.handleWatchEvent(ctx, ev)
-			}
-			err := r.setHeadRevision(ctx, "default", watchResponse.Header.Revision)
+			// Events that failed to be processed are replayed by not advancing the head revision beyond the revision
+			// preceding them and reestablishing the watch
+			head, replay := r.processWatchEvents(ctx, watchResponse.Events, watchResponse.Header.Revision, replays,
+				r.handleWatchEvent)
+			err := r.setHeadRevision(ctx, "default", head)
 			if err != nil {
 				retrySleep(err)
 				break
 			}
+			if replay {
+				time.Sleep(headRevisionOpRetryPeriod)
+				cancel()
+				break
+			}
+		}
+	}
+}
+
+// processWatchEvents dispatches the given events to the given handler in order. Returns the head revision, i.e., the
+// given revision up to which the events have been processed, unless an event failed to be processed and has to be
+// replayed, in which case the revision preceding the event is returned along with true. The given replays count how
+// often the events of a revision have been replayed so far. Events that still fail to be processed after having been
+// replayed maxEventReplays times are skipped, such that a single broken event doesn't block all subsequent ones.
+func (r *TupleGenerationJobReconciler) processWatchEvents(ctx context.Context, events []*clientv3.Event, revision int64, replays map[int64]int, handle func(context.Context, *clientv3.Event) error) (int64, bool) {
+	for _, ev := range events {
+		rev := ev.Kv.ModRevision
+		if err := handle(ctx, ev); err != nil {
+			if replays[rev] < maxEventReplays {
+				replays[rev]++
+				r.Logger.Error(err, "Failed to process roster event - replaying", "Revision", rev,
+					"Replay", replays[rev])
+				return rev - 1, true
+			}
+			r.Logger.Error(err, "Failed to process roster event too often - skipping", "Revision", rev,
+				"Key", string(ev.Kv.Key), "Type", ev.Type)
 		}
+		delete(replays, rev)
 	}
+	return revision, false
 }
 
 // handleWatchEvent inspects the given event and dispatches to handleRemoteTaskUpdate or handleJobUpdate based on the
-// type of contained key.
-func (r *TupleGenerationJobReconciler) handleWatchEvent(ctx context.Context, ev *clientv3.Event) {
+// type of contained key. Returns an error in case the event has to be replayed.
+func (r *TupleGenerationJobReconciler) handleWatchEvent(ctx context.Context, ev *clientv3.Event) error {
 	key, err := ParseKey(string(ev.Kv.Key))
 	if err != nil {
 		r.Logger.Error(err, "Unexpected etcd watch event", "Event.Key", ev.Kv.Key)
-		return
+		return nil
 	}
 	logger := r.Logger.WithValues("Key", key, "Value", string(ev.Kv.Value), "Type", ev.Type)
 	logger.V(logging.DEBUG).Info("Processing roster event")
@@ -352,57 +520,75 @@ func (r *TupleGenerationJobReconciler) handleWatchEvent(ctx context.Context, ev
 		local, err := isLocalTaskKey(ctx, &r.Client, k)
 		if err != nil {
 			logger.Error(err, "Failed to check task type")
-			return
+			return nil
 		}
 		if local {
-			return
+			return nil
 		}
 		r.handleRemoteTaskUpdate(ctx, k, ev)
 	case RosterKey:
-		r.handleJobUpdate(ctx, k, ev)
+		return r.handleJobUpdate(ctx, k, ev)
 	default:
 		panic(fmt.Sprintf("Unexpected key type encountered: %v", key))
 	}
+	return nil
 }
 
-func (r *TupleGenerationJobReconciler) handleJobUpdate(ctx context.Context, key RosterKey, ev *clientv3.Event) {
+// handleJobUpdate creates, starts, cancels, fails, and deletes local jobs according to their rosters. Returns an error
+// in case the job could not be created or updated, such that the event is replayed.
+func (r *TupleGenerationJobReconciler) handleJobUpdate(ctx context.Context, key RosterKey, ev *clientv3.Event) error {
 	logger := r.Logger.WithValues("Key", key)
 	switch ev.Type {
 	case mvccpb.PUT:
+		playerID, err := localPlayerID(ctx, &r.Client, key.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to read local player ID: %w", err)
+		}
 		// Get job spec from etcd K/V pair
-		jobSpec := &klyshkov1alpha1.TupleGenerationJobSpec{}
-		err := json.Unmarshal(ev.Kv.Value, jobSpec)
+		roster := &rosterJob{}
+		err = json.Unmarshal(ev.Kv.Value, roster)
 		if err != nil {
 			logger.Error(err, "Failed to unmarshal spec")
-			return
+			return nil
 		}
-		// TODO Create or update depending on whether Job already exists
-		err = r.createJobIfNotExists(ctx, key.NamespacedName, jobSpec)
-		if err != nil {
-			logger.Error(err, "Failed to create job")
-			return
+		// Jobs are created on the coordinator before their roster is, so there is nothing to create there
+		if playerID != coordinatorPlayerID {
+			// TODO Create or update depending on whether Job already exists
+			err = r.createJobIfNotExists(ctx, key.NamespacedName, roster)
+			if err != nil {
+				return fmt.Errorf("failed to create job %v: %w", key.Name, err)
+			}
+			logger.V(logging.DEBUG).Info("Job created")
+		}
+		// Start jobs admitted by the coordinator
+		if playerID != coordinatorPlayerID {
+			if err := r.applyRosterAdmission(ctx, key.NamespacedName, *roster); err != nil {
+				return fmt.Errorf("failed to apply admission of job %v: %w", key.Name, err)
+			}
+		}
+		// Apply cancellations and failures recorded in the roster by any VCP
+		if err := r.applyRosterVerdict(ctx, key.NamespacedName, *roster); err != nil {
+			return fmt.Errorf("failed to apply verdict for job %v: %w", key.Name, err)
 		}
-		logger.V(logging.DEBUG).Info("Job created")
 	case mvccpb.DELETE:
 		// Delete job iff exists
 		found := &klyshkov1alpha1.TupleGenerationJob{}
 		err := r.Client.Get(ctx, key.NamespacedName, found)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
-				return
+				return nil
 			}
-			logger.Error(err, "Failed to read job resource")
-			return
+			return fmt.Errorf("failed to read resource for job %v: %w", key.Name, err)
 		}
 		err = r.Delete(ctx, found)
-		if err != nil {
-			logger.Error(err, "Job deletion failed")
-			return
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete job %v: %w", key.Name, err)
 		}
 		logger.V(logging.DEBUG).Info("Job deleted")
 	default:
 		panic(fmt.Sprintf("Unexpected etcd event encounter: %v", ev))
 	}
+	return nil
 }
 
 // handleRemoteTaskUpdate is responsible for creating, updating, and deleting local tasks and proxies for remote tasks.
@@ -495,22 +681,46 @@ func (r *TupleGenerationJobReconciler) handleRemoteTaskUpdate(ctx context.Contex
 	}
 }
 
-// createJobIfNotExists creates a job according to the given TupleGenerationJobSpec.
-func (r *TupleGenerationJobReconciler) createJobIfNotExists(ctx context.Context, name types.NamespacedName, jobSpec *klyshkov1alpha1.TupleGenerationJobSpec) error {
+// createJobIfNotExists creates a job according to the given roster. The job is marked as being mirrored from the
+// roster. Shards are controlled by the sharded job they belong to.
+func (r *TupleGenerationJobReconciler) createJobIfNotExists(ctx context.Context, name types.NamespacedName, roster *rosterJob) error {
 	logger := r.Logger.WithValues("Job.Name", name)
 	found := &klyshkov1alpha1.TupleGenerationJob{}
 	err := r.Client.Get(ctx, name, found)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
+			labels := map[string]string{MirroredJobLabel: "true"}
+			for k, v := range shardLabels(roster.Shard) {
+				labels[k] = v
+			}
 			job := &klyshkov1alpha1.TupleGenerationJob{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      name.Name,
 					Namespace: name.Namespace,
+					Labels:    labels,
 				},
-				Spec: *jobSpec,
+				Spec: roster.TupleGenerationJobSpec,
+			}
+			if roster.Shard != nil {
+				// Lookup sharded job (requires retry as it has been mirrored from its roster just before and might
+				// take small period of time to be available from API server)
+				parent := &klyshkov1alpha1.TupleGenerationJob{}
+				err := retry.Do(func() error {
+					return r.Get(ctx, types.NamespacedName{Namespace: name.Namespace, Name: roster.Shard.Parent}, parent)
+				})
+				if err != nil {
+					return fmt.Errorf("failed to read sharded job %v: %w", roster.Shard.Parent, err)
+				}
+				if err := ctrl.SetControllerReference(parent, job, r.Scheme); err != nil {
+					return fmt.Errorf("setting owner reference failed: %w", err)
+				}
 			}
 			logger.V(logging.DEBUG).Info("Creating a new job")
-			return r.Create(ctx, job)
+			err = r.Create(ctx, job)
+			if apierrors.IsAlreadyExists(err) {
+				return nil
+			}
+			return err
 		}
 		return err
 	}
@@ -522,5 +732,6 @@ func (r *TupleGenerationJobReconciler) SetupWithManager(mgr ctrl.Manager) error
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&klyshkov1alpha1.TupleGenerationJob{}).
 		Owns(&klyshkov1alpha1.TupleGenerationTask{}).
+		Owns(&klyshkov1alpha1.TupleGenerationJob{}).
 		Complete(r)
 }
@@ -1,5 +1,5 @@
 /*
-Copyright (c) 2022-2023 - for information on the respective copyright owner
+Copyright (c) 2022-2026 - for information on the respective copyright owner
 see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
 
 SPDX-License-Identifier: Apache-2.0
@@ -14,16 +14,22 @@ import (
 	"github.com/carbynestack/klyshko/castor"
 	"github.com/carbynestack/klyshko/logging"
 	"github.com/google/uuid"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"math/rand"
+	"reflect"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
 )
@@ -31,11 +37,44 @@ import (
 // PeriodicReconciliationDuration is the maximum time between two successive reconciliations
 const PeriodicReconciliationDuration = 10 * time.Second
 
+// jobOwnerKey is the name of the field index used to look up the jobs controlled by a scheduler.
+const jobOwnerKey = ".metadata.controller"
+
 // TupleGenerationSchedulerReconciler reconciles a TupleGenerationScheduler object.
 type TupleGenerationSchedulerReconciler struct {
 	client.Client
-	Scheme       *runtime.Scheme
-	CastorClient *castor.Client
+	Scheme           *runtime.Scheme
+	EtcdClient       *clientv3.Client
+	CastorClient     *castor.Client
+	StrategyRegistry *SchedulingStrategyRegistry
+
+	// Drain puts the reconciler in drain mode where no new jobs are created by any scheduler.
+	Drain bool
+
+	// strategies caches the strategy instances used by the schedulers indexed by scheduler name.
+	strategies      map[types.NamespacedName]schedulerStrategy
+	strategiesMutex sync.Mutex
+}
+
+// schedulerStrategy is a strategy instance together with the specification it has been created from.
+type schedulerStrategy struct {
+	spec     klyshkov1alpha1.SchedulingStrategySpec
+	strategy SchedulingStrategy
+}
+
+// NewTupleGenerationSchedulerReconciler creates a TupleGenerationSchedulerReconciler. In case drain is true, no new jobs
+// are created by any scheduler. In case no etcd client is given, telemetry data is neither published nor combined with
+// the data published by other VCPs.
+func NewTupleGenerationSchedulerReconciler(client client.Client, scheme *runtime.Scheme, etcdClient *clientv3.Client, castorClient *castor.Client, strategyRegistry *SchedulingStrategyRegistry, drain bool) *TupleGenerationSchedulerReconciler {
+	return &TupleGenerationSchedulerReconciler{
+		Client:           client,
+		Scheme:           scheme,
+		EtcdClient:       etcdClient,
+		CastorClient:     castorClient,
+		StrategyRegistry: strategyRegistry,
+		Drain:            drain,
+		strategies:       map[types.NamespacedName]schedulerStrategy{},
+	}
 }
 
 //+kubebuilder:rbac:groups=klyshko.carbnyestack.io,resources=tuplegenerationschedulers,verbs=get;list;watch;create;update;patch;delete
@@ -53,77 +92,462 @@ func (r *TupleGenerationSchedulerReconciler) Reconcile(ctx context.Context, req
 	err := r.Get(ctx, req.NamespacedName, scheduler)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			// Scheduler resource not available -> has been deleted
+			// Scheduler resource not available -> has been deleted, forget about the strategy and metrics of it
+			r.forgetStrategy(req.NamespacedName)
+			forgetSchedulerMetrics(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		return ctrl.Result{}, fmt.Errorf("failed to read scheduler resource: %w", err)
 	}
 
-	// Remove all finished jobs
-	if r.cleanupFinishedJobs(ctx, scheduler) != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to delete finished jobs: %w", err)
+	// Run the scheduler and record the observations made along the way in its status
+	status := scheduler.Status.DeepCopy()
+	result, err := r.schedule(ctx, scheduler)
+	retainTimestamps(status, &scheduler.Status)
+	persisted := &scheduler.Status
+	if !reflect.DeepEqual(status, &scheduler.Status) {
+		if updateErr := r.Status().Update(ctx, scheduler); updateErr != nil {
+			logger.Error(updateErr, "Updating scheduler status failed")
+			persisted = status
+			if err == nil {
+				err = fmt.Errorf("failed to update status of scheduler %v: %w", req.NamespacedName, updateErr)
+			}
+		}
+	}
+
+	// Remove finished jobs only after the failures and budget records observed for them have been persisted, such that
+	// they are not lost in case updating the status fails
+	if len(persisted.ObservedJobs) > 0 {
+		if cleanupErr := r.cleanupFinishedJobs(ctx, scheduler, persisted); cleanupErr != nil && err == nil {
+			err = fmt.Errorf("failed to delete finished jobs: %w", cleanupErr)
+		}
 	}
+	return result, err
+}
 
-	// Fetch active jobs
-	activeJobs, err := r.getMatchingJobs(ctx, func(job klyshkov1alpha1.TupleGenerationJob) bool {
-		return !job.Status.State.IsDone()
+// schedule creates a tuple generation job for the given scheduler, iff the scheduling strategy configured for the
+// scheduler decides to do so. The status of the given scheduler is updated in place but not persisted.
+func (r *TupleGenerationSchedulerReconciler) schedule(ctx context.Context, scheduler *klyshkov1alpha1.TupleGenerationScheduler) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// Stay passive unless running on the coordinating VCP, as jobs only make progress if the coordinator writes a roster
+	playerID, coordinating, err := r.checkCoordinator(ctx, scheduler)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: PeriodicReconciliationDuration}, err
+	}
+	if !coordinating {
+		// Remove all finished jobs, as failures and budgets are not tracked on VCPs other than the coordinator
+		if err := r.cleanupFinishedJobs(ctx, scheduler, nil); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete finished jobs: %w", err)
+		}
+		// Publish the local telemetry data for the coordinator to take it into account
+		if err := r.publishLocalTelemetry(ctx, scheduler, playerID); err != nil {
+			logger.Error(err, "Publishing telemetry data failed")
+		}
+		return ctrl.Result{RequeueAfter: PeriodicReconciliationDuration}, nil
+	}
+
+	// Fetch jobs
+	now := time.Now()
+	jobs, err := r.getMatchingJobs(ctx, scheduler, func(job klyshkov1alpha1.TupleGenerationJob) bool {
+		return true
 	})
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to fetch active jobs: %w", err)
+		return ctrl.Result{}, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	// Track failures of finished jobs and move circuit breakers whose cool-down elapsed to half-open
+	failures := newFailureTracker(scheduler.Spec.FailurePolicy, scheduler.Status.Failures)
+	scheduler.Status.ObservedJobs = failures.observe(jobs, scheduler.Status.ObservedJobs)
+	failures.advance(now)
+	scheduler.Status.Failures = failures.status()
+
+	// Account finished jobs against the budgets of the tuple types
+	budgets := newBudgetTracker(scheduler.Spec.TupleTypePolicies, scheduler.Status.BudgetHistory)
+	budgets.observe(jobs, now)
+	scheduler.Status.BudgetHistory = budgets.status()
+
+	// Look up the strategy configured for the scheduler and report in case it can't be instantiated
+	strategy, err := r.getStrategy(types.NamespacedName{Namespace: scheduler.Namespace, Name: scheduler.Name},
+		scheduler.Spec.Strategy)
+	if err != nil {
+		logger.Error(err, "Scheduling strategy not accepted", "Strategy", scheduler.Spec.Strategy.Name)
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerStrategyAccepted, metav1.ConditionFalse,
+			"InvalidStrategy", err.Error())
+		return ctrl.Result{}, nil
+	}
+	setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerStrategyAccepted, metav1.ConditionTrue,
+		"StrategyInstantiated", fmt.Sprintf("Using scheduling strategy '%s'", scheduler.Spec.Strategy.Name))
+
+	// Evaluate the generation windows and blackouts declared for the scheduler
+	window, err := evaluateWindows(scheduler.Spec, now)
+	if err != nil {
+		logger.Error(err, "Evaluating windows failed")
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerWindowOpen, metav1.ConditionFalse, InvalidWindow,
+			err.Error())
+		return ctrl.Result{}, nil
+	}
+	windowStatus := metav1.ConditionFalse
+	if window.open {
+		windowStatus = metav1.ConditionTrue
 	}
+	setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerWindowOpen, windowStatus, window.reason, window.message)
 
-	// Stop if already at maximum concurrency level
+	// Filter for active jobs
+	var activeJobs []klyshkov1alpha1.TupleGenerationJob
+	for _, job := range jobs {
+		if !job.Status.State.IsDone() {
+			activeJobs = append(activeJobs, job)
+		}
+	}
 	activeJobCount := len(activeJobs)
-	if scheduler.Spec.Concurrency <= activeJobCount {
+	scheduler.Status.ActiveJobs = activeJobCount
+
+	// Stop if suspended or draining and report whether all jobs finished
+	if scheduler.Spec.Suspend || r.Drain {
+		reason, mode := "Suspended", "Scheduler is suspended"
+		if r.Drain {
+			reason, mode = "OperatorDraining", "Operator is in drain mode"
+		}
+		if activeJobCount > 0 {
+			logger.V(logging.DEBUG).Info("Draining - do nothing", "Jobs.Active", activeJobCount)
+			setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerDrained, metav1.ConditionFalse, reason,
+				fmt.Sprintf("%s, waiting for %d active jobs to finish", mode, activeJobCount))
+		} else {
+			logger.V(logging.DEBUG).Info("Drained - do nothing")
+			setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerDrained, metav1.ConditionTrue, reason,
+				fmt.Sprintf("%s, all jobs finished", mode))
+		}
+		return ctrl.Result{RequeueAfter: PeriodicReconciliationDuration}, nil
+	}
+	meta.RemoveStatusCondition(&scheduler.Status.Conditions, klyshkov1alpha1.SchedulerDrained)
+
+	// Collect available tuple generators into map according to their supported tuple types
+	generatorsByTupleType, err := r.getGeneratorsByTupleType(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Filter policies declared on scheduler resource by removing those policies for which no generator can be selected
+	policies, candidatesByTupleType, unserviceablePolicies := r.getServiceablePolicies(ctx, scheduler, generatorsByTupleType)
+	scheduler.Status.ServiceablePolicies = nil
+	for _, policy := range policies {
+		scheduler.Status.ServiceablePolicies = append(scheduler.Status.ServiceablePolicies, policy.Type)
+	}
+	scheduler.Status.UnserviceablePolicies = unserviceablePolicies
+	if len(unserviceablePolicies) == 0 {
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerPoliciesServiceable, metav1.ConditionTrue,
+			"AllPoliciesServiceable", "Tuples can be generated for all tuple types")
+	} else {
+		var unserviceableTypes []string
+		for _, policy := range unserviceablePolicies {
+			unserviceableTypes = append(unserviceableTypes, policy.Type)
+		}
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerPoliciesServiceable, metav1.ConditionFalse,
+			"UnserviceablePolicies", fmt.Sprintf("Tuples can't be generated for tuple types %v", unserviceableTypes))
+	}
+
+	// Stop if already at maximum concurrency level. In case burst mode is enabled, the regular concurrency level is
+	// checked after fetching telemetry data, as it may be exceeded.
+	atConcurrencyLimit := func() (ctrl.Result, error) {
 		logger.V(logging.DEBUG).Info("At maximum concurrency level - do nothing", "Jobs.Active", activeJobCount, "Scheduler.Concurrency", scheduler.Spec.Concurrency)
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerAtConcurrencyLimit, metav1.ConditionTrue,
+			"ConcurrencyLimitReached", fmt.Sprintf("%d of %d jobs active", activeJobCount, scheduler.Spec.Concurrency))
 		return ctrl.Result{RequeueAfter: PeriodicReconciliationDuration}, nil
 	}
+	if scheduler.Spec.Concurrency <= activeJobCount && (!isBurstEnabled(scheduler) || scheduler.Spec.BurstConcurrency <= activeJobCount) {
+		return atConcurrencyLimit()
+	}
 
 	// Fetch telemetry data from Castor
 	telemetry, err := r.CastorClient.GetTelemetry(ctx)
 	if err != nil {
 		logger.Error(err, "Fetching telemetry data from Castor failed", "Castor.URL", r.CastorClient.URL)
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerCastorReachable, metav1.ConditionFalse,
+			"TelemetryUnavailable", err.Error())
 		return ctrl.Result{RequeueAfter: PeriodicReconciliationDuration}, err
 	}
+	setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerCastorReachable, metav1.ConditionTrue,
+		"TelemetryFetched", "Telemetry data has been fetched from Castor")
+	scheduler.Status.LastTelemetry = toTelemetrySnapshot(telemetry)
 
-	// Collect available tuple generators into map according to their supported tuple types
-	generatorsByTupleType, err := r.getGeneratorsByTupleType(ctx)
-	if err != nil {
-		return ctrl.Result{}, err
+	// Combine with the telemetry data published by the other VCPs, as tuples are only usable if available on all VCPs
+	if r.EtcdClient != nil {
+		combined, err := r.combineWithPublishedTelemetry(ctx, scheduler.Namespace, playerID, telemetry, now)
+		if err != nil {
+			logger.Error(err, "Combining telemetry data of all VCPs failed")
+			setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerTelemetryConsistent, metav1.ConditionFalse,
+				"TelemetryUnavailable", err.Error())
+			return ctrl.Result{RequeueAfter: PeriodicReconciliationDuration}, err
+		}
+		logger.V(logging.DEBUG).Info("Combined telemetry data of all VCPs", "Telemetry", combined.telemetry,
+			"Divergence", combined.divergence, "MissingPlayers", combined.missing)
+		telemetry = combined.telemetry
+		scheduler.Status.LastTelemetry = toTelemetrySnapshot(telemetry)
+		recordTelemetryConsistency(scheduler, scheduler.Status.LastTelemetry, combined)
 	}
 
-	// Filter policies declared on scheduler resource by removing those policies for which no generator is available
-	policies := r.getServiceablePolicies(ctx, scheduler, generatorsByTupleType)
+	// Enter or leave burst mode depending on whether tuple types dropped below their emergency threshold
+	bursting := trackBurstEpisode(scheduler, getCriticalTupleTypes(policies, telemetry), now)
+	beyondConcurrency := scheduler.Spec.Concurrency <= activeJobCount
+	if beyondConcurrency && !bursting {
+		return atConcurrencyLimit()
+	}
+	if beyondConcurrency {
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerAtConcurrencyLimit, metav1.ConditionTrue,
+			"BurstingBeyondConcurrencyLimit", fmt.Sprintf("%d of %d jobs active, bursting up to %d jobs",
+				activeJobCount, scheduler.Spec.Concurrency, scheduler.Spec.BurstConcurrency))
+	} else {
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerAtConcurrencyLimit, metav1.ConditionFalse,
+			"BelowConcurrencyLimit", fmt.Sprintf("%d of %d jobs active", activeJobCount, scheduler.Spec.Concurrency))
+	}
+
+	// Apply fill targets, per tuple type concurrency limits and emergency overrides for closed windows
+	policies, scheduler.Status.TupleTypes = ApplyTupleTypePolicies(policies, withInflightTuples(telemetry, activeJobs),
+		activeJobs, scheduler.Status.TupleTypes, window.open)
+
+	// Exclude tuple types and generators that are backing off after failures or whose circuit breaker is open
+	policies = failures.filter(policies, candidatesByTupleType, activeJobs, now)
 
-	// Decide for which tuple type to generate tuples for next based on (for now fixed) strategy
-	var strategy SchedulingStrategy = &LotterySchedulingStrategy{}
-	tupleType := strategy.Schedule(ctx, telemetry, policies, activeJobs)
+	// Exclude tuple types whose budget is used up
+	policies = budgets.filter(policies, scheduler.Status.TupleTypes, candidatesByTupleType, activeJobs, now)
+
+	// Jobs beyond the regular concurrency are only created for tuple types below their emergency threshold
+	if beyondConcurrency {
+		policies = getEmergencyPolicies(policies, scheduler.Status.TupleTypes)
+	}
+
+	// Decide for which tuple type to generate tuples for next based on the configured strategy
+	tupleType, err := strategy.Schedule(ctx, telemetry, policies, activeJobs)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("scheduler strategy failed to decide on a tuple type: %w", err)
+	}
+	decision := &klyshkov1alpha1.SchedulingDecision{Time: metav1.Now()}
+	scheduler.Status.LastDecision = decision
 	if tupleType == nil {
 		logger.Info("Scheduler strategy decided not to generate tuples")
 		return ctrl.Result{RequeueAfter: PeriodicReconciliationDuration}, nil
 	}
 	logger.Info("Scheduler strategy has decided to generate tuples", "TupleType", tupleType)
+	decision.TupleType = *tupleType
 
-	// Take the first tuple generator available for the given tuple type
-	var generator klyshkov1alpha1.TupleGenerator
-	if generators, exists := generatorsByTupleType[*tupleType]; exists && len(generators) > 0 {
-		generator = generators[0]
-	} else {
+	// Select the tuple generator to be used for the given tuple type according to the policy
+	policyIdx := getPolicyIndex(policies, *tupleType)
+	if policyIdx < 0 {
 		return ctrl.Result{}, fmt.Errorf("scheduler strategy returned a tuple type without an associated policy: %s", *tupleType)
 	}
+	selection := policies[policyIdx].GeneratorSelection
+	selected := selectGenerator(*tupleType, selection, candidatesByTupleType[*tupleType], jobs)
+	if selected == nil {
+		return ctrl.Result{}, fmt.Errorf("no generator can be selected for tuple type: %s", *tupleType)
+	}
+	generator := *selected
+	logger.Info("Generator selected", "TupleType", tupleType, "Generator", generator.Name)
+	decision.Generator = generator.Name
+
+	// Size the job according to the deficit of the selected tuple type
+	tupleTypeSpec := generator.Spec.GetTupleTypeSpec(*tupleType)
+	if tupleTypeSpec == nil {
+		return ctrl.Result{}, errors.New(fmt.Sprintf("tuple type '%s' is not supported by generator '%s'", *tupleType, types.NamespacedName{
+			Namespace: generator.Namespace,
+			Name:      generator.Name,
+		}))
+	}
+	available := getAvailableTuples(withInflightTuples(telemetry, activeJobs), *tupleType)
+	count := JobSize(policies[policyIdx], tupleTypeSpec.BatchSize, available)
+	count = limitJobSize(count, tupleTypeSpec.BatchSize, budgets.remainingTuples(*tupleType, activeJobs, now))
+	logger.V(logging.DEBUG).Info("Job sized", "TupleType", tupleType, "Available.WithInflight", available,
+		"Generator.BatchSize", tupleTypeSpec.BatchSize, "Count", count)
+	if count == 0 {
+		logger.Info("Remaining budget does not suffice for a single batch of the selected generator",
+			"TupleType", tupleType, "Generator", generator.Name)
+		return ctrl.Result{RequeueAfter: PeriodicReconciliationDuration}, nil
+	}
+	decision.Count = count
 
 	// Create job for selected tuple type
-	err = r.createJob(ctx, scheduler, generator, *tupleType)
+	job, err := r.createJob(ctx, scheduler, generator, *tupleType, count)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to create tuple generation job: %w", err)
 	}
+	decision.Job = job.Name
+	if beyondConcurrency {
+		logger.Info("Job created beyond regular concurrency level", "Job", job.Name, "Jobs.Active", activeJobCount)
+		recordBurstJob(scheduler)
+	}
+	scheduler.Status.ActiveJobs++
+	for idx := range scheduler.Status.TupleTypes {
+		if scheduler.Status.TupleTypes[idx].Type == *tupleType {
+			scheduler.Status.TupleTypes[idx].ActiveJobs++
+		}
+	}
 
 	return ctrl.Result{
 		RequeueAfter: PeriodicReconciliationDuration,
 	}, nil
 }
 
+// checkCoordinator checks whether the given scheduler runs on the coordinating VCP and records the outcome in the
+// coordinating condition of the scheduler. Schedulers on other VCPs delete all jobs they control, as jobs created
+// locally never get a roster and therefore would wait forever.
+func (r *TupleGenerationSchedulerReconciler) checkCoordinator(ctx context.Context, scheduler *klyshkov1alpha1.TupleGenerationScheduler) (uint, bool, error) {
+	logger := log.FromContext(ctx)
+	playerID, err := localPlayerID(ctx, &r.Client, scheduler.Namespace)
+	if err != nil {
+		logger.Error(err, "Reading VCP configuration failed")
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerCoordinating, metav1.ConditionFalse,
+			"VCPConfigUnavailable", err.Error())
+		return 0, false, fmt.Errorf("can't read playerId from VCP configuration: %w", err)
+	}
+	if playerID == coordinatorPlayerID {
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerCoordinating, metav1.ConditionTrue,
+			"CoordinatingVCP", "Scheduler runs on the coordinating VCP")
+		return playerID, true, nil
+	}
+
+	// Jobs mirrored from the roster of the coordinator are not controlled by the scheduler and therefore are retained
+	orphans, err := r.getMatchingJobs(ctx, scheduler, func(job klyshkov1alpha1.TupleGenerationJob) bool {
+		return metav1.IsControlledBy(&job, scheduler)
+	})
+	if err != nil {
+		return playerID, false, fmt.Errorf("failed to fetch orphan jobs: %w", err)
+	}
+	for _, j := range orphans {
+		logger.Info("Deleting orphan job created on non-coordinating VCP", "Job", j.Name)
+		if err := r.Delete(ctx, &j); err != nil && !apierrors.IsNotFound(err) {
+			return playerID, false, fmt.Errorf("failed to delete orphan job %s: %w", j.Name, err)
+		}
+	}
+	logger.V(logging.DEBUG).Info("Not on coordinating VCP - do nothing", "PlayerID", playerID)
+	scheduler.Status.ActiveJobs = 0
+	setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerCoordinating, metav1.ConditionFalse, "NotCoordinatingVCP",
+		fmt.Sprintf("Scheduler runs on VCP of player %d, jobs are only scheduled by the VCP of player %d", playerID,
+			coordinatorPlayerID))
+	return playerID, false, nil
+}
+
+// publishLocalTelemetry fetches the telemetry data from the local Castor service and publishes it on behalf of the
+// given player for the coordinating VCP to take it into account. The snapshot of the telemetry data and the Castor
+// reachability condition of the given scheduler are updated accordingly.
+func (r *TupleGenerationSchedulerReconciler) publishLocalTelemetry(ctx context.Context, scheduler *klyshkov1alpha1.TupleGenerationScheduler, playerID uint) error {
+	if r.EtcdClient == nil {
+		return nil
+	}
+	telemetry, err := r.CastorClient.GetTelemetry(ctx)
+	if err != nil {
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerCastorReachable, metav1.ConditionFalse,
+			"TelemetryUnavailable", err.Error())
+		return fmt.Errorf("failed to fetch telemetry data from Castor: %w", err)
+	}
+	setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerCastorReachable, metav1.ConditionTrue,
+		"TelemetryFetched", "Telemetry data has been fetched from Castor")
+	scheduler.Status.LastTelemetry = toTelemetrySnapshot(telemetry)
+	return publishTelemetry(ctx, r.EtcdClient, scheduler.Namespace, playerTelemetry{
+		PlayerID:  playerID,
+		Time:      time.Now(),
+		Telemetry: telemetry,
+	})
+}
+
+// combineWithPublishedTelemetry publishes the given telemetry data fetched by the given player from the local Castor
+// service and combines it with the telemetry data published by the other players of the VCC.
+func (r *TupleGenerationSchedulerReconciler) combineWithPublishedTelemetry(ctx context.Context, namespace string, playerID uint, telemetry castor.Telemetry, now time.Time) (vccTelemetry, error) {
+	playerCount, err := numberOfVCPs(ctx, &r.Client, namespace)
+	if err != nil {
+		return vccTelemetry{}, fmt.Errorf("can't read playerCount from VCP configuration: %w", err)
+	}
+	local := playerTelemetry{PlayerID: playerID, Time: now, Telemetry: telemetry}
+	if err := publishTelemetry(ctx, r.EtcdClient, namespace, local); err != nil {
+		return vccTelemetry{}, err
+	}
+	published, err := fetchTelemetry(ctx, r.EtcdClient, namespace)
+	if err != nil {
+		return vccTelemetry{}, err
+	}
+	all := []playerTelemetry{local}
+	for _, data := range published {
+		if data.PlayerID != playerID {
+			all = append(all, data)
+		}
+	}
+	return combineTelemetry(all, playerCount, now), nil
+}
+
+// getStrategy returns the strategy instance for the scheduler with the given name. A new instance is created in case
+// the scheduler has not been seen before or its strategy specification has changed since the instance was created.
+func (r *TupleGenerationSchedulerReconciler) getStrategy(name types.NamespacedName, spec klyshkov1alpha1.SchedulingStrategySpec) (SchedulingStrategy, error) {
+	r.strategiesMutex.Lock()
+	defer r.strategiesMutex.Unlock()
+	if r.strategies == nil {
+		r.strategies = map[types.NamespacedName]schedulerStrategy{}
+	}
+	if cached, exists := r.strategies[name]; exists && reflect.DeepEqual(cached.spec, spec) {
+		return cached.strategy, nil
+	}
+	delete(r.strategies, name)
+	strategy, err := r.StrategyRegistry.New(spec)
+	if err != nil {
+		return nil, err
+	}
+	r.strategies[name] = schedulerStrategy{
+		spec:     *spec.DeepCopy(),
+		strategy: strategy,
+	}
+	return strategy, nil
+}
+
+// forgetStrategy drops the strategy instance for the scheduler with the given name.
+func (r *TupleGenerationSchedulerReconciler) forgetStrategy(name types.NamespacedName) {
+	r.strategiesMutex.Lock()
+	defer r.strategiesMutex.Unlock()
+	delete(r.strategies, name)
+}
+
+// setSchedulerCondition sets the condition of the given type on the status of the given scheduler.
+func setSchedulerCondition(scheduler *klyshkov1alpha1.TupleGenerationScheduler, conditionType string, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&scheduler.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: scheduler.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// retainTimestamps keeps the points in time of the telemetry snapshot and the scheduling decision recorded in the given
+// previous status in case the content of the snapshot or the decision in the given current status did not change, such
+// that the status is not updated in every reconciliation.
+func retainTimestamps(previous *klyshkov1alpha1.TupleGenerationSchedulerStatus, current *klyshkov1alpha1.TupleGenerationSchedulerStatus) {
+	if previous.LastTelemetry != nil && current.LastTelemetry != nil {
+		snapshot := current.LastTelemetry.DeepCopy()
+		snapshot.Time = previous.LastTelemetry.Time
+		if reflect.DeepEqual(snapshot, previous.LastTelemetry) {
+			current.LastTelemetry.Time = previous.LastTelemetry.Time
+		}
+	}
+	if previous.LastDecision != nil && current.LastDecision != nil {
+		decision := *current.LastDecision
+		decision.Time = previous.LastDecision.Time
+		if decision == *previous.LastDecision {
+			current.LastDecision.Time = previous.LastDecision.Time
+		}
+	}
+}
+
+// toTelemetrySnapshot converts the given telemetry data fetched from Castor into a snapshot taken now.
+func toTelemetrySnapshot(telemetry castor.Telemetry) *klyshkov1alpha1.TelemetrySnapshot {
+	snapshot := &klyshkov1alpha1.TelemetrySnapshot{Time: metav1.Now()}
+	for _, m := range telemetry.TupleMetrics {
+		snapshot.TupleTypes = append(snapshot.TupleTypes, klyshkov1alpha1.TupleTypeTelemetry{
+			Type:            m.TupleType,
+			Available:       m.Available,
+			ConsumptionRate: m.ConsumptionRate,
+		})
+	}
+	return snapshot
+}
+
 // getGeneratorsByTupleType collects available tuple generators into a map indexed by tuple type
 func (r *TupleGenerationSchedulerReconciler) getGeneratorsByTupleType(ctx context.Context) (map[string][]klyshkov1alpha1.TupleGenerator, error) {
 	generators := klyshkov1alpha1.TupleGeneratorList{}
@@ -145,40 +569,96 @@ func (r *TupleGenerationSchedulerReconciler) getGeneratorsByTupleType(ctx contex
 }
 
 // getServiceablePolicies filters the policies declared on the given scheduler resource by removing those policies for
-// which no or more than a single generator is available in the given map of generators.
+// which no generator can be selected from the given map of generators. The candidate generators for the tuple types of
+// the remaining policies are returned as well. The removed policies are returned separately along with the reason for
+// their removal.
 func (r *TupleGenerationSchedulerReconciler) getServiceablePolicies(
 	ctx context.Context,
 	scheduler *klyshkov1alpha1.TupleGenerationScheduler,
-	generatorsByTupleType map[string][]klyshkov1alpha1.TupleGenerator) []klyshkov1alpha1.TupleTypePolicy {
+	generatorsByTupleType map[string][]klyshkov1alpha1.TupleGenerator) ([]klyshkov1alpha1.TupleTypePolicy, map[string][]candidateGenerator, []klyshkov1alpha1.UnserviceablePolicy) {
 	logger := log.FromContext(ctx)
 	var policies []klyshkov1alpha1.TupleTypePolicy
+	candidatesByTupleType := map[string][]candidateGenerator{}
+	var unserviceablePolicies []klyshkov1alpha1.UnserviceablePolicy
+	unserviceable := func(policy klyshkov1alpha1.TupleTypePolicy, reason string, message string) {
+		logger.Info("Tuple type can't be generated", "TupleType", policy.Type, "Reason", reason, "Message", message)
+		unserviceablePolicies = append(unserviceablePolicies, klyshkov1alpha1.UnserviceablePolicy{
+			Type:    policy.Type,
+			Reason:  reason,
+			Message: message,
+		})
+	}
 	for _, policy := range scheduler.Spec.TupleTypePolicies {
-		if generators, exists := generatorsByTupleType[policy.Type]; exists {
-			if len(generators) > 1 {
-				logger.Info("More than one generator available for tuple type - will not be generated", "TupleType", policy.Type)
-			} else {
-				policies = append(policies, policy)
-			}
-		} else {
-			logger.Info("No generator available for tuple type - will not be generated", "TupleType", policy.Type)
+		generators, exists := generatorsByTupleType[policy.Type]
+		if !exists {
+			unserviceable(policy, klyshkov1alpha1.NoGeneratorAvailable, "No generator available")
+			continue
+		}
+		candidates, err := getCandidateGenerators(policy.GeneratorSelection, generators)
+		if err != nil {
+			unserviceable(policy, klyshkov1alpha1.InvalidGeneratorSelection, err.Error())
+			continue
+		}
+		if selectGenerator(policy.Type, policy.GeneratorSelection, candidates, nil) == nil {
+			unserviceable(policy, klyshkov1alpha1.NoGeneratorSelectable,
+				fmt.Sprintf("None of the %d available generators is selectable", len(generators)))
+			continue
 		}
+		policies = append(policies, policy)
+		candidatesByTupleType[policy.Type] = candidates
 	}
-	return policies
+	return policies, candidatesByTupleType, unserviceablePolicies
+}
+
+// getAvailableTuples returns the number of tuples of the given type available according to the given telemetry data.
+func getAvailableTuples(telemetry castor.Telemetry, tupleType string) int {
+	for _, m := range telemetry.TupleMetrics {
+		if m.TupleType == tupleType {
+			return m.Available
+		}
+	}
+	return 0
+}
+
+// JobSize computes the number of tuples to be generated by a job for the tuple type of the given policy. In case
+// the policy enables adaptive batch sizing, the job size is derived from the deficit, i.e., the number of tuples
+// missing to reach the threshold of the policy given the number of available tuples (including in-flight ones). While
+// the tuple type is being filled, the threshold is the fill target as set by ApplyTupleTypePolicies. The deficit is
+// rounded up to a multiple of the given batch size of the generator and bounded by the minimum and maximum batch
+// size of the policy, both rounded to multiples of the generator batch size as well. A job always generates at least
+// a single generator batch.
+func JobSize(policy klyshkov1alpha1.TupleTypePolicy, batchSize int, available int) int {
+	if policy.MinBatchSize <= 0 && policy.MaxBatchSize <= 0 {
+		return batchSize
+	}
+	roundUp := func(n int) int {
+		return (n + batchSize - 1) / batchSize * batchSize
+	}
+	lower := batchSize
+	if roundUp(policy.MinBatchSize) > lower {
+		lower = roundUp(policy.MinBatchSize)
+	}
+	size := roundUp(policy.Threshold - available)
+	if size < lower {
+		size = lower
+	}
+	if policy.MaxBatchSize > 0 {
+		upper := policy.MaxBatchSize / batchSize * batchSize
+		if upper < lower {
+			upper = lower
+		}
+		if size > upper {
+			size = upper
+		}
+	}
+	return size
 }
 
 // Creates a tuple generation job for the given tuple type in the namespace where the scheduler lives in.
-func (r *TupleGenerationSchedulerReconciler) createJob(ctx context.Context, scheduler *klyshkov1alpha1.TupleGenerationScheduler, generator klyshkov1alpha1.TupleGenerator, tupleType string) error {
+func (r *TupleGenerationSchedulerReconciler) createJob(ctx context.Context, scheduler *klyshkov1alpha1.TupleGenerationScheduler, generator klyshkov1alpha1.TupleGenerator, tupleType string, count int) (*klyshkov1alpha1.TupleGenerationJob, error) {
 	logger := log.FromContext(ctx)
 	jobID := uuid.New().String()
 
-	tupleTypeSpec := generator.Spec.GetTupleTypeSpec(tupleType)
-	if tupleTypeSpec == nil {
-		return errors.New(fmt.Sprintf("tuple type '%s' is not supported by generator '%s'", tupleType, types.NamespacedName{
-			Namespace: generator.Namespace,
-			Name:      generator.Name,
-		}))
-	}
-
 	job := &klyshkov1alpha1.TupleGenerationJob{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "klyshko.carbnyestack.io/v1alpha1",
@@ -189,38 +669,55 @@ func (r *TupleGenerationSchedulerReconciler) createJob(ctx context.Context, sche
 			Namespace: scheduler.Namespace,
 		},
 		Spec: klyshkov1alpha1.TupleGenerationJobSpec{
-			ID:        jobID,
-			Type:      tupleType,
-			Count:     tupleTypeSpec.BatchSize,
-			Generator: generator.Name,
+			ID:           jobID,
+			Type:         tupleType,
+			Count:        count,
+			Generator:    generator.Name,
+			Priority:     scheduler.Spec.JobPriority,
+			RetryPolicy:  scheduler.Spec.RetryPolicy.DeepCopy(),
+			TaskTimeouts: scheduler.Spec.TaskTimeouts.DeepCopy(),
+			Sharding:     scheduler.Spec.JobSharding.DeepCopy(),
 		},
 		Status: klyshkov1alpha1.TupleGenerationJobStatus{
 			State:                   klyshkov1alpha1.JobPending,
 			LastStateTransitionTime: metav1.Now(),
 		},
 	}
+	if scheduler.Spec.JobDeadlineSeconds != nil {
+		deadline := *scheduler.Spec.JobDeadlineSeconds
+		job.Spec.ActiveDeadlineSeconds = &deadline
+	}
 	err := ctrl.SetControllerReference(scheduler, job, r.Scheme)
 	if err != nil {
 		logger.Error(err, "could not set owner reference on job", "Job", job)
-		return err
+		return nil, err
 	}
 	err = r.Create(ctx, job)
 	if err != nil {
 		logger.Error(err, "job creation failed", "Job", job)
-		return err
+		return nil, err
 	}
 	logger.Info("Job created", "Job", job)
-	return nil
+	return job, nil
 }
 
-// Deletes all jobs that are done, i.e., either complete or failed, and beyond the TTL
-func (r *TupleGenerationSchedulerReconciler) cleanupFinishedJobs(ctx context.Context, scheduler *klyshkov1alpha1.TupleGenerationScheduler) error {
+// Deletes all jobs that are done, i.e., either complete or failed, and beyond the TTL. Failed jobs are retained until
+// they have been retried. In case the given persisted status is not nil, jobs are retained as well until they are among
+// the observed jobs recorded in it. As jobs are observed for tracking failures and accounted against the budgets in the
+// same reconciliation, the budget records of the jobs are persisted by then as well.
+func (r *TupleGenerationSchedulerReconciler) cleanupFinishedJobs(ctx context.Context, scheduler *klyshkov1alpha1.TupleGenerationScheduler, persisted *klyshkov1alpha1.TupleGenerationSchedulerStatus) error {
 	logger := log.FromContext(ctx)
-	finishedJobs, err := r.getMatchingJobs(ctx, func(job klyshkov1alpha1.TupleGenerationJob) bool {
+	observed := map[types.UID]bool{}
+	if persisted != nil {
+		for _, o := range persisted.ObservedJobs {
+			observed[o.UID] = true
+		}
+	}
+	finishedJobs, err := r.getMatchingJobs(ctx, scheduler, func(job klyshkov1alpha1.TupleGenerationJob) bool {
 		isBeyondTTL := func() bool {
 			return time.Now().After(job.Status.LastStateTransitionTime.Add(time.Duration(scheduler.Spec.TTLSecondsAfterFinished) * time.Second))
 		}
-		return job.Status.State.IsDone() && isBeyondTTL()
+		return job.Status.State.IsDone() && isBeyondTTL() && !isRetryPending(job) && (persisted == nil || observed[job.UID])
 	})
 	if err != nil {
 		logger.Error(err, "failed to fetch finished jobs")
@@ -242,11 +739,11 @@ func (r *TupleGenerationSchedulerReconciler) cleanupFinishedJobs(ctx context.Con
 	return nil
 }
 
-// Returns all jobs that match the given predicate.
-func (r *TupleGenerationSchedulerReconciler) getMatchingJobs(ctx context.Context, pred func(klyshkov1alpha1.TupleGenerationJob) bool) ([]klyshkov1alpha1.TupleGenerationJob, error) {
+// Returns all jobs controlled by the given scheduler that match the given predicate.
+func (r *TupleGenerationSchedulerReconciler) getMatchingJobs(ctx context.Context, scheduler *klyshkov1alpha1.TupleGenerationScheduler, pred func(klyshkov1alpha1.TupleGenerationJob) bool) ([]klyshkov1alpha1.TupleGenerationJob, error) {
 	logger := log.FromContext(ctx)
 	allJobs := &klyshkov1alpha1.TupleGenerationJobList{}
-	err := r.List(ctx, allJobs)
+	err := r.List(ctx, allJobs, client.InNamespace(scheduler.Namespace), client.MatchingFields{jobOwnerKey: scheduler.Name})
 	if err != nil {
 		return nil, err
 	}
@@ -260,10 +757,26 @@ func (r *TupleGenerationSchedulerReconciler) getMatchingJobs(ctx context.Context
 	return matchingJobs, nil
 }
 
+// controllingScheduler extracts the name of the scheduler controlling the given object for indexing.
+func controllingScheduler(obj client.Object) []string {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.APIVersion != klyshkov1alpha1.GroupVersion.String() ||
+		owner.Kind != "TupleGenerationScheduler" {
+		return nil
+	}
+	return []string{owner.Name}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *TupleGenerationSchedulerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Index jobs by the name of the scheduler controlling them
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &klyshkov1alpha1.TupleGenerationJob{}, jobOwnerKey,
+		controllingScheduler)
+	if err != nil {
+		return fmt.Errorf("failed to index jobs by controlling scheduler: %w", err)
+	}
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&klyshkov1alpha1.TupleGenerationScheduler{}).
+		For(&klyshkov1alpha1.TupleGenerationScheduler{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Owns(&klyshkov1alpha1.TupleGenerationJob{}).
 		Complete(r)
 }
@@ -0,0 +1,475 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"strconv"
+	"time"
+)
+
+// newFakeSchedulerReconciler creates a scheduler reconciler running on the coordinating VCP backed by a fake client
+// populated with the given objects.
+func newFakeSchedulerReconciler(castorClient *castor.Client, objs ...client.Object) *TupleGenerationSchedulerReconciler {
+	return newFakeSchedulerReconcilerForPlayer(coordinatorPlayerID, castorClient, objs...)
+}
+
+// newFakeSchedulerReconcilerForPlayer creates a scheduler reconciler running on the VCP of the given player of a
+// two-party VCC backed by a fake client populated with the given objects.
+func newFakeSchedulerReconcilerForPlayer(playerID int, castorClient *castor.Client, objs ...client.Object) *TupleGenerationSchedulerReconciler {
+	s := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+	Expect(klyshkov1alpha1.AddToScheme(s)).To(Succeed())
+	vcpConfig := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: vcpConfigMapName, Namespace: "default"},
+		Data:       map[string]string{"playerCount": "2", "playerId": strconv.Itoa(playerID)},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(vcpConfig).WithObjects(objs...).Build()
+	return NewTupleGenerationSchedulerReconciler(c, s, nil, castorClient, NewSchedulingStrategyRegistry(), false)
+}
+
+// failingStatusClient is a client failing to update the status of resources, e.g., due to conflicts.
+type failingStatusClient struct {
+	client.Client
+}
+
+// Status returns a status writer failing all updates.
+func (c failingStatusClient) Status() client.StatusWriter {
+	return failingStatusWriter{}
+}
+
+// failingStatusWriter is a status writer failing all updates.
+type failingStatusWriter struct{}
+
+// Update fails.
+func (failingStatusWriter) Update(context.Context, client.Object, ...client.UpdateOption) error {
+	return errors.New("status update failed")
+}
+
+// Patch fails.
+func (failingStatusWriter) Patch(context.Context, client.Object, client.Patch, ...client.PatchOption) error {
+	return errors.New("status update failed")
+}
+
+// newTestScheduler creates a scheduler with a single policy for the given tuple type.
+func newTestScheduler(tupleType string) *klyshkov1alpha1.TupleGenerationScheduler {
+	return &klyshkov1alpha1.TupleGenerationScheduler{
+		ObjectMeta: metav1.ObjectMeta{Name: "scheduler", Namespace: "default", UID: "scheduler-uid"},
+		Spec: klyshkov1alpha1.TupleGenerationSchedulerSpec{
+			Concurrency:             1,
+			TTLSecondsAfterFinished: 600,
+			TupleTypePolicies: []klyshkov1alpha1.TupleTypePolicy{
+				{Type: tupleType, Threshold: 1000, Priority: 1},
+			},
+			Strategy: klyshkov1alpha1.SchedulingStrategySpec{Name: LotterySchedulingStrategyName},
+		},
+	}
+}
+
+// newTestJob creates a job for the given tuple type in the given state that is controlled by the given scheduler.
+func newTestJob(scheduler *klyshkov1alpha1.TupleGenerationScheduler, name string, tupleType string, state klyshkov1alpha1.TupleGenerationJobState) *klyshkov1alpha1.TupleGenerationJob {
+	job := &klyshkov1alpha1.TupleGenerationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: scheduler.Namespace, UID: types.UID(name)},
+		Spec:       klyshkov1alpha1.TupleGenerationJobSpec{ID: name, Type: tupleType, Count: 1000, Generator: "generator"},
+		Status: klyshkov1alpha1.TupleGenerationJobStatus{
+			State:                   state,
+			LastStateTransitionTime: metav1.Now(),
+		},
+	}
+	gvk := klyshkov1alpha1.GroupVersion.WithKind("TupleGenerationScheduler")
+	job.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(scheduler, gvk)})
+	return job
+}
+
+// reconcileScheduler runs a single reconciliation for the given scheduler and returns its updated state.
+func reconcileScheduler(r *TupleGenerationSchedulerReconciler, scheduler *klyshkov1alpha1.TupleGenerationScheduler) *klyshkov1alpha1.TupleGenerationScheduler {
+	key := types.NamespacedName{Namespace: scheduler.Namespace, Name: scheduler.Name}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	Expect(err).NotTo(HaveOccurred())
+	updated := &klyshkov1alpha1.TupleGenerationScheduler{}
+	Expect(r.Get(context.Background(), key, updated)).To(Succeed())
+	return updated
+}
+
+var _ = Describe("Filtering serviceable policies", func() {
+	const TupleTypeA = "A"
+	const TupleTypeB = "B"
+	const TupleTypeC = "C"
+	const TupleTypeD = "D"
+	scheduler := &klyshkov1alpha1.TupleGenerationScheduler{
+		Spec: klyshkov1alpha1.TupleGenerationSchedulerSpec{
+			TupleTypePolicies: []klyshkov1alpha1.TupleTypePolicy{
+				{Type: TupleTypeA, Threshold: 1, Priority: 1},
+				{Type: TupleTypeB, Threshold: 1, Priority: 1},
+				{Type: TupleTypeC, Threshold: 1, Priority: 1},
+				{Type: TupleTypeD, Threshold: 1, Priority: 1, GeneratorSelection: &klyshkov1alpha1.GeneratorSelectionSpec{
+					Generators: []klyshkov1alpha1.GeneratorPreference{{Name: "unknown", Weight: 1}},
+				}},
+			},
+		},
+	}
+	generatorsByTupleType := map[string][]klyshkov1alpha1.TupleGenerator{
+		TupleTypeA: {generator("a", nil)},
+		TupleTypeB: {generator("b1", nil), generator("b2", nil)},
+		TupleTypeD: {generator("d", nil)},
+	}
+
+	It("keeps policies with multiple generators and reports the reason for others being unserviceable", func() {
+		r := &TupleGenerationSchedulerReconciler{}
+		policies, candidates, unserviceable := r.getServiceablePolicies(context.Background(), scheduler, generatorsByTupleType)
+		Expect(policies).To(HaveLen(2))
+		Expect(policies[0].Type).To(Equal(TupleTypeA))
+		Expect(policies[1].Type).To(Equal(TupleTypeB))
+		Expect(candidates[TupleTypeB]).To(HaveLen(2))
+		Expect(unserviceable).To(HaveLen(2))
+		Expect(unserviceable[0].Type).To(Equal(TupleTypeC))
+		Expect(unserviceable[0].Reason).To(Equal(klyshkov1alpha1.NoGeneratorAvailable))
+		Expect(unserviceable[1].Type).To(Equal(TupleTypeD))
+		Expect(unserviceable[1].Reason).To(Equal(klyshkov1alpha1.NoGeneratorSelectable))
+	})
+})
+
+var _ = Describe("Recording scheduler status", func() {
+	It("converts Castor telemetry into a snapshot", func() {
+		snapshot := toTelemetrySnapshot(castor.Telemetry{TupleMetrics: []castor.TupleMetrics{
+			{Available: 42, ConsumptionRate: 7, TupleType: "A"},
+		}})
+		Expect(snapshot.Time.IsZero()).To(BeFalse())
+		Expect(snapshot.TupleTypes).To(Equal([]klyshkov1alpha1.TupleTypeTelemetry{
+			{Type: "A", Available: 42, ConsumptionRate: 7},
+		}))
+	})
+
+	It("keeps the transition time of conditions that did not change their status", func() {
+		scheduler := &klyshkov1alpha1.TupleGenerationScheduler{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerCastorReachable, metav1.ConditionTrue, "A", "")
+		first := *meta.FindStatusCondition(scheduler.Status.Conditions, klyshkov1alpha1.SchedulerCastorReachable)
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerCastorReachable, metav1.ConditionTrue, "B", "")
+		second := *meta.FindStatusCondition(scheduler.Status.Conditions, klyshkov1alpha1.SchedulerCastorReachable)
+		Expect(second.Reason).To(Equal("B"))
+		Expect(second.ObservedGeneration).To(Equal(int64(3)))
+		Expect(second.LastTransitionTime).To(Equal(first.LastTransitionTime))
+	})
+})
+
+var _ = Describe("Reconciling schedulers repeatedly", func() {
+	const TupleType = "A"
+
+	BeforeEach(func() {
+		httpmock.Activate()
+		respondWithTelemetry(TupleType, 5000)
+	})
+
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	It("does not update the status in case nothing changed", func() {
+		scheduler := newTestScheduler(TupleType)
+		r := newFakeSchedulerReconciler(castor.NewClient(testCastorURL), scheduler, testGenerator(TupleType))
+		first := reconcileScheduler(r, scheduler)
+		Expect(first.Status.LastTelemetry).NotTo(BeNil())
+		Expect(first.Status.LastDecision).NotTo(BeNil())
+
+		time.Sleep(1100 * time.Millisecond)
+		second := reconcileScheduler(r, scheduler)
+		Expect(second.ResourceVersion).To(Equal(first.ResourceVersion))
+		Expect(second.Status.LastTelemetry.Time).To(Equal(first.Status.LastTelemetry.Time))
+		Expect(second.Status.LastDecision.Time).To(Equal(first.Status.LastDecision.Time))
+	})
+
+	It("updates the time of the telemetry snapshot in case the telemetry changed", func() {
+		scheduler := newTestScheduler(TupleType)
+		r := newFakeSchedulerReconciler(castor.NewClient(testCastorURL), scheduler, testGenerator(TupleType))
+		first := reconcileScheduler(r, scheduler)
+
+		time.Sleep(1100 * time.Millisecond)
+		respondWithTelemetry(TupleType, 6000)
+		second := reconcileScheduler(r, scheduler)
+		Expect(second.Status.LastTelemetry.Time.After(first.Status.LastTelemetry.Time.Time)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Sizing jobs", func() {
+	const BatchSize = 1000
+	policy := klyshkov1alpha1.TupleTypePolicy{Type: "A", Threshold: 10000, Priority: 1}
+
+	When("adaptive batch sizing is disabled", func() {
+		It("generates a single batch", func() {
+			Expect(JobSize(policy, BatchSize, 0)).To(Equal(BatchSize))
+		})
+	})
+
+	When("adaptive batch sizing is enabled", func() {
+		adaptive := policy
+		adaptive.MinBatchSize = 1500
+		adaptive.MaxBatchSize = 7500
+
+		It("rounds the deficit up to a multiple of the generator batch size", func() {
+			Expect(JobSize(adaptive, BatchSize, 5500)).To(Equal(5000))
+		})
+		It("tops up a nearly full pool using the minimum batch size", func() {
+			Expect(JobSize(adaptive, BatchSize, 9900)).To(Equal(2000))
+		})
+		It("fills an empty pool using the maximum batch size", func() {
+			Expect(JobSize(adaptive, BatchSize, 0)).To(Equal(7000))
+		})
+		It("uses the minimum batch size for tuple types above threshold", func() {
+			Expect(JobSize(adaptive, BatchSize, 20000)).To(Equal(2000))
+		})
+		It("generates at least a single generator batch", func() {
+			small := policy
+			small.MaxBatchSize = 10
+			Expect(JobSize(small, BatchSize, 0)).To(Equal(BatchSize))
+		})
+	})
+})
+
+var _ = Describe("Indexing jobs", func() {
+	It("indexes jobs by the name of the controlling scheduler", func() {
+		scheduler := &klyshkov1alpha1.TupleGenerationScheduler{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: klyshkov1alpha1.GroupVersion.String(),
+				Kind:       "TupleGenerationScheduler",
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: "scheduler", UID: "uid"},
+		}
+		job := &klyshkov1alpha1.TupleGenerationJob{}
+		job.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(scheduler, scheduler.GroupVersionKind())})
+		Expect(controllingScheduler(job)).To(Equal([]string{"scheduler"}))
+	})
+	It("does not index jobs not controlled by a scheduler", func() {
+		Expect(controllingScheduler(&klyshkov1alpha1.TupleGenerationJob{})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Suspending schedulers", func() {
+	const TupleType = "A"
+
+	It("does not create new jobs and reports when all jobs finished", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.Suspend = true
+		job := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobRunning)
+		r := newFakeSchedulerReconciler(nil, scheduler, job)
+
+		updated := reconcileScheduler(r, scheduler)
+		drained := meta.FindStatusCondition(updated.Status.Conditions, klyshkov1alpha1.SchedulerDrained)
+		Expect(drained).NotTo(BeNil())
+		Expect(drained.Status).To(Equal(metav1.ConditionFalse))
+		Expect(drained.Reason).To(Equal("Suspended"))
+
+		Expect(r.Get(context.Background(), client.ObjectKeyFromObject(job), job)).To(Succeed())
+		job.Status.State = klyshkov1alpha1.JobCompleted
+		Expect(r.Update(context.Background(), job)).To(Succeed())
+		updated = reconcileScheduler(r, scheduler)
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, klyshkov1alpha1.SchedulerDrained)).To(BeTrue())
+
+		jobs := &klyshkov1alpha1.TupleGenerationJobList{}
+		Expect(r.List(context.Background(), jobs)).To(Succeed())
+		Expect(jobs.Items).To(HaveLen(1))
+	})
+
+	It("does not create new jobs while the operator is draining", func() {
+		scheduler := newTestScheduler(TupleType)
+		r := newFakeSchedulerReconciler(nil, scheduler)
+		r.Drain = true
+
+		updated := reconcileScheduler(r, scheduler)
+		drained := meta.FindStatusCondition(updated.Status.Conditions, klyshkov1alpha1.SchedulerDrained)
+		Expect(drained).NotTo(BeNil())
+		Expect(drained.Status).To(Equal(metav1.ConditionTrue))
+		Expect(drained.Reason).To(Equal("OperatorDraining"))
+	})
+})
+
+// testCastorURL is the URL of the Castor service mocked by respondWithTelemetry.
+const testCastorURL = "http://cs-castor.default.svc.cluster.local:10100"
+
+// respondWithTelemetry mocks the Castor service to respond with the given number of available tuples of the given
+// type.
+func respondWithTelemetry(tupleType string, available int) {
+	responder, err := httpmock.NewJsonResponder(200, castor.Telemetry{TupleMetrics: []castor.TupleMetrics{
+		{TupleType: tupleType, Available: available},
+	}})
+	Expect(err).NotTo(HaveOccurred())
+	httpmock.RegisterResponder("GET", testCastorURL+"/intra-vcp/telemetry", responder)
+}
+
+// testGenerator creates a generator supporting the given tuple type with a batch size of 1000.
+func testGenerator(tupleType string) *klyshkov1alpha1.TupleGenerator {
+	g := generator("generator", nil)
+	g.Spec.Supports = []klyshkov1alpha1.TupleTypeSpec{{Type: tupleType, BatchSize: 1000}}
+	return &g
+}
+
+var _ = Describe("Enforcing budgets when reconciling", func() {
+	const TupleType = "A"
+
+	BeforeEach(func() {
+		httpmock.Activate()
+		respondWithTelemetry(TupleType, 0)
+	})
+
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	It("records finished jobs and does not create jobs beyond the budget", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.TupleTypePolicies[0].Budget = &klyshkov1alpha1.GenerationBudget{MaxJobsPerHour: 1}
+		job := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobCompleted)
+		r := newFakeSchedulerReconciler(castor.NewClient(testCastorURL), scheduler, job, testGenerator(TupleType))
+
+		updated := reconcileScheduler(r, scheduler)
+		Expect(updated.Status.BudgetHistory).To(HaveLen(1))
+		Expect(updated.Status.BudgetHistory[0].Job).To(Equal("job"))
+		Expect(updated.Status.TupleTypes).To(HaveLen(1))
+		Expect(updated.Status.TupleTypes[0].BudgetExhausted).To(BeTrue())
+		Expect(updated.Status.LastDecision.TupleType).To(BeEmpty())
+
+		jobs := &klyshkov1alpha1.TupleGenerationJobList{}
+		Expect(r.List(context.Background(), jobs)).To(Succeed())
+		Expect(jobs.Items).To(HaveLen(1))
+	})
+
+	It("shrinks jobs to the remaining tuple budget", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.TupleTypePolicies[0].Threshold = 10000
+		scheduler.Spec.TupleTypePolicies[0].MaxBatchSize = 10000
+		scheduler.Spec.TupleTypePolicies[0].Budget = &klyshkov1alpha1.GenerationBudget{MaxTuples: 4000}
+		job := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobCompleted)
+		r := newFakeSchedulerReconciler(castor.NewClient(testCastorURL), scheduler, job, testGenerator(TupleType))
+
+		updated := reconcileScheduler(r, scheduler)
+		Expect(updated.Status.LastDecision.TupleType).To(Equal(TupleType))
+		Expect(updated.Status.LastDecision.Count).To(Equal(3000))
+	})
+})
+
+var _ = Describe("Bursting beyond the concurrency", func() {
+	const TupleType = "A"
+
+	BeforeEach(func() {
+		httpmock.Activate()
+	})
+
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	It("creates jobs for tuple types below their emergency threshold and records the episode", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.BurstConcurrency = 2
+		scheduler.Spec.TupleTypePolicies[0].Threshold = 10000
+		scheduler.Spec.TupleTypePolicies[0].EmergencyThreshold = 5000
+		job := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobRunning)
+		r := newFakeSchedulerReconciler(castor.NewClient(testCastorURL), scheduler, job, testGenerator(TupleType))
+
+		respondWithTelemetry(TupleType, 0)
+		updated := reconcileScheduler(r, scheduler)
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, klyshkov1alpha1.SchedulerBursting)).To(BeTrue())
+		Expect(updated.Status.LastDecision.Job).NotTo(BeEmpty())
+		Expect(updated.Status.BurstEpisodes).To(HaveLen(1))
+		Expect(updated.Status.BurstEpisodes[0].TupleTypes).To(Equal([]string{TupleType}))
+		Expect(updated.Status.BurstEpisodes[0].Jobs).To(Equal(1))
+		Expect(updated.Status.BurstEpisodes[0].EndTime).To(BeNil())
+
+		respondWithTelemetry(TupleType, 10000)
+		Expect(r.Get(context.Background(), client.ObjectKeyFromObject(job), job)).To(Succeed())
+		job.Status.State = klyshkov1alpha1.JobCompleted
+		Expect(r.Update(context.Background(), job)).To(Succeed())
+		updated = reconcileScheduler(r, scheduler)
+		Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, klyshkov1alpha1.SchedulerBursting)).To(BeTrue())
+		Expect(updated.Status.BurstEpisodes).To(HaveLen(1))
+		Expect(updated.Status.BurstEpisodes[0].EndTime).NotTo(BeNil())
+	})
+
+	It("does not exceed the concurrency for tuple types above their emergency threshold", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.BurstConcurrency = 2
+		scheduler.Spec.TupleTypePolicies[0].EmergencyThreshold = 500
+		job := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobRunning)
+		r := newFakeSchedulerReconciler(castor.NewClient(testCastorURL), scheduler, job, testGenerator(TupleType))
+
+		respondWithTelemetry(TupleType, 100)
+		updated := reconcileScheduler(r, scheduler)
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, klyshkov1alpha1.SchedulerBursting)).To(BeTrue())
+		Expect(updated.Status.LastDecision.TupleType).To(BeEmpty())
+		Expect(updated.Status.BurstEpisodes[0].Jobs).To(BeZero())
+	})
+
+	It("does not burst in case burst mode is disabled", func() {
+		scheduler := newTestScheduler(TupleType)
+		scheduler.Spec.TupleTypePolicies[0].EmergencyThreshold = 5000
+		job := newTestJob(scheduler, "job", TupleType, klyshkov1alpha1.JobRunning)
+		r := newFakeSchedulerReconciler(castor.NewClient(testCastorURL), scheduler, job, testGenerator(TupleType))
+
+		respondWithTelemetry(TupleType, 0)
+		updated := reconcileScheduler(r, scheduler)
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, klyshkov1alpha1.SchedulerBursting)).To(BeNil())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, klyshkov1alpha1.SchedulerAtConcurrencyLimit)).To(BeTrue())
+		Expect(updated.Status.BurstEpisodes).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Coordinating schedulers", func() {
+	const TupleType = "A"
+
+	BeforeEach(func() {
+		httpmock.Activate()
+		respondWithTelemetry(TupleType, 0)
+	})
+
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	It("schedules jobs on the coordinating VCP", func() {
+		scheduler := newTestScheduler(TupleType)
+		r := newFakeSchedulerReconciler(castor.NewClient(testCastorURL), scheduler, testGenerator(TupleType))
+
+		updated := reconcileScheduler(r, scheduler)
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, klyshkov1alpha1.SchedulerCoordinating)).To(BeTrue())
+		Expect(updated.Status.LastDecision.TupleType).To(Equal(TupleType))
+	})
+
+	It("stays passive and deletes orphan jobs on other VCPs", func() {
+		scheduler := newTestScheduler(TupleType)
+		orphan := newTestJob(scheduler, "orphan", TupleType, klyshkov1alpha1.JobPending)
+		mirrored := newTestJob(scheduler, "mirrored", TupleType, klyshkov1alpha1.JobRunning)
+		mirrored.SetOwnerReferences(nil)
+		r := newFakeSchedulerReconcilerForPlayer(1, castor.NewClient(testCastorURL), scheduler, orphan, mirrored,
+			testGenerator(TupleType))
+
+		updated := reconcileScheduler(r, scheduler)
+		coordinating := meta.FindStatusCondition(updated.Status.Conditions, klyshkov1alpha1.SchedulerCoordinating)
+		Expect(coordinating).NotTo(BeNil())
+		Expect(coordinating.Status).To(Equal(metav1.ConditionFalse))
+		Expect(coordinating.Reason).To(Equal("NotCoordinatingVCP"))
+		Expect(updated.Status.LastDecision).To(BeNil())
+
+		jobs := &klyshkov1alpha1.TupleGenerationJobList{}
+		Expect(r.List(context.Background(), jobs)).To(Succeed())
+		Expect(jobs.Items).To(ConsistOf(HaveField("Name", "mirrored")))
+	})
+})
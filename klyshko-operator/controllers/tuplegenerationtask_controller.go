@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/carbynestack/klyshko/logging"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -26,7 +27,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
 )
@@ -35,6 +39,10 @@ const (
 	// TaskLabel is used to identify target pods for the inter-CRG service
 	TaskLabel = "klyshko.carbnyestack.io/task-ref"
 
+	// OwnerTaskLabel identifies the task a PVC, service, or pod has been created for, such that resources left behind
+	// by deleted tasks can be garbage collected.
+	OwnerTaskLabel = "klyshko.carbnyestack.io/owner-task"
+
 	// InterCRGNetworkingPort is the network used for inter-CRG communication.
 	InterCRGNetworkingPort = 5000
 )
@@ -107,7 +115,11 @@ func (r *TupleGenerationTaskReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{}, fmt.Errorf("failed to read resource for roster entry with key %v for task %v: %w", taskKey, req.Name, err)
 	}
 	if resp.Count == 0 {
-		status, err := json.Marshal(&klyshkov1alpha1.TupleGenerationTaskStatus{State: klyshkov1alpha1.TaskPreparing})
+		now := metav1.Now()
+		status, err := json.Marshal(&klyshkov1alpha1.TupleGenerationTaskStatus{
+			State:                   klyshkov1alpha1.TaskPreparing,
+			LastStateTransitionTime: &now,
+		})
 		_, err = r.EtcdClient.Put(ctx, taskKey.ToEtcdKey(), string(status))
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to create roster entry for task %v: %w", req.Name, err)
@@ -138,6 +150,34 @@ func (r *TupleGenerationTaskReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{}, fmt.Errorf("unable to update status for task %v: %w", req.Name, err)
 	}
 
+	// Cancel the task in case the job has been cancelled and fail the task in case the job failed or the task did not
+	// leave its current state in time. Otherwise, make sure the task is reconciled again when the timeout for its
+	// current state elapses.
+	waiting := ctrl.Result{}
+	if !status.State.IsDone() {
+		if job.Status.State == klyshkov1alpha1.JobCancelled {
+			return ctrl.Result{
+				Requeue: true,
+			}, r.cancelTask(ctx, *taskKey, task, status)
+		}
+		if job.Status.State == klyshkov1alpha1.JobFailed {
+			return ctrl.Result{
+				Requeue: true,
+			}, r.failTask(ctx, *taskKey, status, klyshkov1alpha1.TaskJobFailed, "Job failed: "+job.Status.Message)
+		}
+		timeout := getTaskTimeout(job.Spec.TaskTimeouts, status.State)
+		if timeout > 0 && status.LastStateTransitionTime != nil {
+			remaining := time.Until(status.LastStateTransitionTime.Add(timeout))
+			if remaining <= 0 {
+				return ctrl.Result{
+					Requeue: true,
+				}, r.failTask(ctx, *taskKey, status, klyshkov1alpha1.TaskTimedOut,
+					fmt.Sprintf("Task did not leave state %s within %v", status.State, timeout))
+			}
+			waiting.RequeueAfter = remaining
+		}
+	}
+
 	// Proceed based on current task state. State changes are performed by first invoking setState which updates
 	// the state in etcd and then re-enqueueing in order to reflect the updated state in the local task representation.
 	switch status.State {
@@ -178,7 +218,7 @@ func (r *TupleGenerationTaskReconciler) Reconcile(ctx context.Context, req ctrl.
 			}
 			if endpoint == nil {
 				logger.V(logging.DEBUG).Info("No endpoint available yet for local task")
-				return ctrl.Result{}, nil
+				return waiting, nil
 			}
 			status.Endpoint = fmt.Sprintf("%s:%d", *endpoint, InterCRGNetworkingPort)
 			err = r.setStatus(ctx, *taskKey, status)
@@ -189,7 +229,7 @@ func (r *TupleGenerationTaskReconciler) Reconcile(ctx context.Context, req ctrl.
 				Requeue: true,
 			}, nil
 		default: // At least one remote endpoint not available
-			return ctrl.Result{}, nil
+			return waiting, nil
 		}
 	case klyshkov1alpha1.TaskLaunching:
 		// Create generator pod if not existing
@@ -218,7 +258,7 @@ func (r *TupleGenerationTaskReconciler) Reconcile(ctx context.Context, req ctrl.
 		case v1.PodFailed:
 			return ctrl.Result{
 				Requeue: true,
-			}, r.setState(ctx, *taskKey, status, klyshkov1alpha1.TaskFailed)
+			}, r.failTask(ctx, *taskKey, status, klyshkov1alpha1.TaskPodFailed, "Generator pod failed")
 		}
 	case klyshkov1alpha1.TaskProvisioning:
 		provPod, err := r.getProvisionerPod(ctx, *taskKey)
@@ -233,26 +273,41 @@ func (r *TupleGenerationTaskReconciler) Reconcile(ctx context.Context, req ctrl.
 		case v1.PodFailed:
 			return ctrl.Result{
 				Requeue: true,
-			}, r.setState(ctx, *taskKey, status, klyshkov1alpha1.TaskFailed)
+			}, r.failTask(ctx, *taskKey, status, klyshkov1alpha1.TaskPodFailed, "Provisioner pod failed")
 		}
-	case klyshkov1alpha1.TaskFailed, klyshkov1alpha1.TaskCompleted:
+	case klyshkov1alpha1.TaskFailed, klyshkov1alpha1.TaskCompleted, klyshkov1alpha1.TaskCancelled:
 		logger.V(logging.DEBUG).Info("Task reached a terminal state")
 		return ctrl.Result{}, r.deletePVC(ctx, taskKey)
 	default:
 		return ctrl.Result{}, fmt.Errorf("unexpected state for Task %v, PVC not reclaimed", req.Name)
 	}
-	return ctrl.Result{}, nil
+	return waiting, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// SetupWithManager sets up the controller with the Manager. Jobs are watched such that the local task of a job is
+// failed as soon as the job fails.
 func (r *TupleGenerationTaskReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&klyshkov1alpha1.TupleGenerationTask{}).
 		Owns(&v1.Pod{}).
 		Owns(&v1.Service{}).
+		Watches(&source.Kind{Type: &klyshkov1alpha1.TupleGenerationJob{}},
+			handler.EnqueueRequestsFromMapFunc(r.localTaskForJob)).
 		Complete(r)
 }
 
+// localTaskForJob maps the given job to a request for reconciling the local task of the job.
+func (r *TupleGenerationTaskReconciler) localTaskForJob(obj client.Object) []reconcile.Request {
+	playerID, err := localPlayerID(context.Background(), &r.Client, obj.GetNamespace())
+	if err != nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      taskName(obj.GetName(), playerID),
+	}}}
+}
+
 // taskKeyFromName creates a RosterEntryKey from the given name and namespace. Expects that the zero-based VCP
 // identifier is appended with a hyphen to the name.
 func taskKeyFromName(namespace string, name string) (*RosterEntryKey, error) {
@@ -326,9 +381,17 @@ func (r *TupleGenerationTaskReconciler) setState(ctx context.Context, taskKey Ro
 	logger := log.FromContext(ctx).WithValues("Task.Key", taskKey)
 	logger.V(logging.DEBUG).Info("Task transitioning into new state", "from", status.State, "to", state)
 	status.State = state
+	now := metav1.Now()
+	status.LastStateTransitionTime = &now
 	return r.setStatus(ctx, taskKey, status)
 }
 
+// failTask transitions the task with the given key into the failed state for the given reason.
+func (r *TupleGenerationTaskReconciler) failTask(ctx context.Context, taskKey RosterEntryKey, status *klyshkov1alpha1.TupleGenerationTaskStatus, reason string, message string) error {
+	status.Reason, status.Message = reason, message
+	return r.setState(ctx, taskKey, status, klyshkov1alpha1.TaskFailed)
+}
+
 // pvcName returns the name of the PVC used for the task with the given key.
 func pvcName(key RosterEntryKey) string {
 	return key.Name + "-" + strconv.Itoa(int(key.PlayerID))
@@ -351,6 +414,9 @@ func (r *TupleGenerationTaskReconciler) getOrCreatePVC(ctx context.Context, key
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name.Name,
 			Namespace: name.Namespace,
+			Labels: map[string]string{
+				OwnerTaskLabel: taskName(key.Name, key.PlayerID),
+			},
 		},
 		Spec: v1.PersistentVolumeClaimSpec{
 			AccessModes: []v1.PersistentVolumeAccessMode{
@@ -381,6 +447,10 @@ func (r *TupleGenerationTaskReconciler) deletePVC(ctx context.Context, key *Rost
 	found := &v1.PersistentVolumeClaim{}
 	err := r.Get(ctx, name, found)
 	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Task terminated before the PVC has been created, or the PVC has been deleted already
+			return nil
+		}
 		return fmt.Errorf("to be deleted persistent volume claim not found for task %v: %w", key, err)
 	}
 
@@ -429,6 +499,9 @@ func (r *TupleGenerationTaskReconciler) createProvisionerPod(ctx context.Context
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name.Name,
 			Namespace: name.Namespace,
+			Labels: map[string]string{
+				OwnerTaskLabel: task.Name,
+			},
 		},
 		Spec: v1.PodSpec{
 			Containers: []v1.Container{{
@@ -565,7 +638,8 @@ func (r *TupleGenerationTaskReconciler) createGeneratorPod(ctx context.Context,
 			Name:      task.Name,
 			Namespace: task.Namespace,
 			Labels: map[string]string{
-				TaskLabel: task.Name,
+				TaskLabel:      task.Name,
+				OwnerTaskLabel: task.Name,
 			},
 		},
 		Spec: v1.PodSpec{
@@ -744,6 +818,9 @@ func (r *TupleGenerationTaskReconciler) getOrCreateService(ctx context.Context,
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name.Name,
 			Namespace: name.Namespace,
+			Labels: map[string]string{
+				OwnerTaskLabel: task.Name,
+			},
 			Annotations: map[string]string{
 				"service.beta.kubernetes.io/port_5000_no_probe_rule": "true",
 			},
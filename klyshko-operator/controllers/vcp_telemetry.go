@@ -0,0 +1,166 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sort"
+	"time"
+)
+
+// MaxTelemetryAge is the age after which the telemetry data published by a player is considered outdated.
+const MaxTelemetryAge = 6 * PeriodicReconciliationDuration
+
+// playerTelemetry is the telemetry data fetched by a player from its local Castor service as published in etcd.
+type playerTelemetry struct {
+	PlayerID  uint             `json:"playerId"`
+	Time      time.Time        `json:"time"`
+	Telemetry castor.Telemetry `json:"telemetry"`
+}
+
+// vccTelemetry is the telemetry data combined from the data published by all players.
+type vccTelemetry struct {
+
+	// telemetry contains the minimum number of tuples available and the maximum consumption rate across all players
+	// per tuple type.
+	telemetry castor.Telemetry
+
+	// divergence is the difference between the highest and the lowest number of available tuples per tuple type.
+	divergence map[string]int
+
+	// missing are the identifiers of the players whose telemetry data is not available or outdated.
+	missing []uint
+}
+
+// publishTelemetry stores the given telemetry data fetched by the given player from its local Castor service in etcd.
+func publishTelemetry(ctx context.Context, etcdClient *clientv3.Client, namespace string, data playerTelemetry) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+	key := TelemetryKey{Namespace: namespace, PlayerID: data.PlayerID}
+	if _, err := etcdClient.Put(ctx, key.ToEtcdKey(), string(encoded)); err != nil {
+		return fmt.Errorf("failed to publish telemetry at %v: %w", key, err)
+	}
+	return nil
+}
+
+// fetchTelemetry reads the telemetry data published by all players for the given namespace from etcd.
+func fetchTelemetry(ctx context.Context, etcdClient *clientv3.Client, namespace string) ([]playerTelemetry, error) {
+	resp, err := etcdClient.Get(ctx, TelemetryPrefix(namespace), clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read telemetry: %w", err)
+	}
+	var published []playerTelemetry
+	for _, kv := range resp.Kvs {
+		data := playerTelemetry{}
+		if err := json.Unmarshal(kv.Value, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode telemetry at %s: %w", kv.Key, err)
+		}
+		published = append(published, data)
+	}
+	return published, nil
+}
+
+// combineTelemetry combines the given telemetry data published by the players of a VCC with the given number of
+// players. Data published by unknown players or older than MaxTelemetryAge at the given point in time is ignored. As
+// tuples can only be used in case they are available on all VCPs, the number of tuples available for a tuple type is
+// the minimum across the players. Tuple types not reported by a player are considered to be not available on the
+// respective VCP.
+func combineTelemetry(published []playerTelemetry, playerCount uint, now time.Time) vccTelemetry {
+	usable := map[uint]playerTelemetry{}
+	for _, data := range published {
+		if data.PlayerID >= playerCount || now.Sub(data.Time) > MaxTelemetryAge {
+			continue
+		}
+		if current, ok := usable[data.PlayerID]; !ok || data.Time.After(current.Time) {
+			usable[data.PlayerID] = data
+		}
+	}
+	result := vccTelemetry{divergence: map[string]int{}}
+	for pid := uint(0); pid < playerCount; pid++ {
+		if _, ok := usable[pid]; !ok {
+			result.missing = append(result.missing, pid)
+		}
+	}
+
+	tupleTypes := map[string]bool{}
+	for _, data := range usable {
+		for _, m := range data.Telemetry.TupleMetrics {
+			tupleTypes[m.TupleType] = true
+		}
+	}
+	for tupleType := range tupleTypes {
+		combined := castor.TupleMetrics{TupleType: tupleType}
+		lowest, highest := -1, 0
+		for _, data := range usable {
+			m := getTupleMetrics(data.Telemetry, tupleType)
+			if lowest < 0 || m.Available < lowest {
+				lowest = m.Available
+			}
+			if m.Available > highest {
+				highest = m.Available
+			}
+			if m.ConsumptionRate > combined.ConsumptionRate {
+				combined.ConsumptionRate = m.ConsumptionRate
+			}
+		}
+		combined.Available = lowest
+		result.telemetry.TupleMetrics = append(result.telemetry.TupleMetrics, combined)
+		if highest > lowest {
+			result.divergence[tupleType] = highest - lowest
+		}
+	}
+	sort.Slice(result.telemetry.TupleMetrics, func(i, j int) bool {
+		return result.telemetry.TupleMetrics[i].TupleType < result.telemetry.TupleMetrics[j].TupleType
+	})
+	return result
+}
+
+// getTupleMetrics returns the metrics for the given tuple type contained in the given telemetry data. In case the
+// tuple type is not contained, metrics reporting no tuples to be available are returned.
+func getTupleMetrics(telemetry castor.Telemetry, tupleType string) castor.TupleMetrics {
+	for _, m := range telemetry.TupleMetrics {
+		if m.TupleType == tupleType {
+			return m
+		}
+	}
+	return castor.TupleMetrics{TupleType: tupleType}
+}
+
+// recordTelemetryConsistency records the divergence and the missing players of the given combined telemetry data in
+// the given telemetry snapshot and sets the telemetry consistency condition of the given scheduler accordingly.
+func recordTelemetryConsistency(scheduler *klyshkov1alpha1.TupleGenerationScheduler, snapshot *klyshkov1alpha1.TelemetrySnapshot, combined vccTelemetry) {
+	var diverging []string
+	for idx := range snapshot.TupleTypes {
+		tupleType := snapshot.TupleTypes[idx].Type
+		snapshot.TupleTypes[idx].Divergence = combined.divergence[tupleType]
+		if combined.divergence[tupleType] > 0 {
+			diverging = append(diverging, tupleType)
+		}
+	}
+	snapshot.MissingPlayers = combined.missing
+	switch {
+	case len(combined.missing) > 0:
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerTelemetryConsistent, metav1.ConditionFalse,
+			"TelemetryMissing", fmt.Sprintf("No recent telemetry published by players %v", combined.missing))
+	case len(diverging) > 0:
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerTelemetryConsistent, metav1.ConditionFalse,
+			"AvailabilityDiverged", fmt.Sprintf("Number of tuples available differs between VCPs for tuple types %v",
+				diverging))
+	default:
+		setSchedulerCondition(scheduler, klyshkov1alpha1.SchedulerTelemetryConsistent, metav1.ConditionTrue,
+			"AvailabilityConsistent", "Number of tuples available is the same on all VCPs")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package controllers
+
+import (
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+var _ = Describe("Combining telemetry of all VCPs", func() {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	published := func(playerID uint, age time.Duration, metrics ...castor.TupleMetrics) playerTelemetry {
+		return playerTelemetry{
+			PlayerID:  playerID,
+			Time:      now.Add(-age),
+			Telemetry: castor.Telemetry{TupleMetrics: metrics},
+		}
+	}
+
+	It("uses keys distinct from roster keys", func() {
+		key := TelemetryKey{Namespace: "default", PlayerID: 1}
+		Expect(key.ToEtcdKey()).To(Equal("/klyshko/telemetry/default/1"))
+		Expect(key.ToEtcdKey()).To(HavePrefix(TelemetryPrefix("default")))
+		_, err := ParseKey(key.ToEtcdKey())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("uses the minimum availability and reports the divergence", func() {
+		combined := combineTelemetry([]playerTelemetry{
+			published(0, 0, castor.TupleMetrics{TupleType: "B", Available: 500, ConsumptionRate: 2},
+				castor.TupleMetrics{TupleType: "A", Available: 1000, ConsumptionRate: 5}),
+			published(1, time.Second, castor.TupleMetrics{TupleType: "A", Available: 800, ConsumptionRate: 7},
+				castor.TupleMetrics{TupleType: "B", Available: 500, ConsumptionRate: 1}),
+		}, 2, now)
+		Expect(combined.telemetry.TupleMetrics).To(Equal([]castor.TupleMetrics{
+			{TupleType: "A", Available: 800, ConsumptionRate: 7},
+			{TupleType: "B", Available: 500, ConsumptionRate: 2},
+		}))
+		Expect(combined.divergence).To(Equal(map[string]int{"A": 200}))
+		Expect(combined.missing).To(BeEmpty())
+	})
+
+	It("considers tuple types not reported by a player to be unavailable", func() {
+		combined := combineTelemetry([]playerTelemetry{
+			published(0, 0, castor.TupleMetrics{TupleType: "A", Available: 1000}),
+			published(1, 0),
+		}, 2, now)
+		Expect(combined.telemetry.TupleMetrics).To(Equal([]castor.TupleMetrics{{TupleType: "A", Available: 0}}))
+		Expect(combined.divergence).To(Equal(map[string]int{"A": 1000}))
+	})
+
+	It("ignores outdated telemetry and that of unknown players", func() {
+		combined := combineTelemetry([]playerTelemetry{
+			published(0, 0, castor.TupleMetrics{TupleType: "A", Available: 1000}),
+			published(1, 2*MaxTelemetryAge, castor.TupleMetrics{TupleType: "A", Available: 0}),
+			published(2, 0, castor.TupleMetrics{TupleType: "A", Available: 0}),
+		}, 2, now)
+		Expect(combined.telemetry.TupleMetrics).To(Equal([]castor.TupleMetrics{{TupleType: "A", Available: 1000}}))
+		Expect(combined.missing).To(Equal([]uint{1}))
+	})
+
+	It("reports divergence and missing players in the scheduler status", func() {
+		scheduler := newTestScheduler("A")
+		combined := combineTelemetry([]playerTelemetry{
+			published(0, 0, castor.TupleMetrics{TupleType: "A", Available: 1000}),
+			published(1, 0, castor.TupleMetrics{TupleType: "A", Available: 600}),
+		}, 2, now)
+		snapshot := toTelemetrySnapshot(combined.telemetry)
+		recordTelemetryConsistency(scheduler, snapshot, combined)
+		Expect(snapshot.TupleTypes).To(Equal([]klyshkov1alpha1.TupleTypeTelemetry{
+			{Type: "A", Available: 600, Divergence: 400},
+		}))
+		condition := meta.FindStatusCondition(scheduler.Status.Conditions, klyshkov1alpha1.SchedulerTelemetryConsistent)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("AvailabilityDiverged"))
+
+		combined = combineTelemetry([]playerTelemetry{
+			published(0, 0, castor.TupleMetrics{TupleType: "A", Available: 1000}),
+		}, 2, now)
+		snapshot = toTelemetrySnapshot(combined.telemetry)
+		recordTelemetryConsistency(scheduler, snapshot, combined)
+		Expect(snapshot.MissingPlayers).To(Equal([]uint{1}))
+		condition = meta.FindStatusCondition(scheduler.Status.Conditions, klyshkov1alpha1.SchedulerTelemetryConsistent)
+		Expect(condition.Reason).To(Equal("TelemetryMissing"))
+	})
+})
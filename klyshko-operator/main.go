@@ -11,6 +11,8 @@ import (
 	"flag"
 	"os"
 	"time"
+	// Embed the time zone database used to evaluate the generation windows of schedulers
+	_ "time/tzdata"
 
 	"github.com/carbynestack/klyshko/castor"
 
@@ -51,6 +53,13 @@ var (
 	castorURL            = flag.String("castor-url", "http://cs-castor.default.svc.cluster.local:10100", "The base url of the castor service used to upload generated tuples.")
 	provisionerImage     = flag.String("provisioner-image", "ghcr.io/carbynestack/klyshko-provisioner:latest", "The name of the provisioner image.")
 	sgxEnabled           = flag.Bool("sgx-enabled", false, "Enable SGX support for tuple generation. When enabled, injects SGX resources, tolerations, and volume mounts.")
+	drain                = flag.Bool("drain", false, "Enable drain mode. When enabled, schedulers do not create new jobs while active jobs run to completion.")
+	maxConcurrentJobs    = flag.Int("max-concurrent-jobs", 0, "The maximum number of jobs generating tuples concurrently. Jobs beyond the limit are queued in order of their priority. Zero means unlimited.")
+	gcInterval           = flag.Duration("gc-interval", 10*time.Minute, "The interval in which orphaned roster keys, head revisions, PVCs, services, and pods are garbage collected. Zero disables garbage collection.")
+	gcGracePeriod        = flag.Duration("gc-grace-period", time.Hour, "The time an etcd key or resource must have been orphaned before it is garbage collected.")
+	gcDryRun             = flag.Bool("gc-dry-run", false, "Report orphaned etcd keys and resources instead of deleting them.")
+	enableWebhooks       = flag.Bool("enable-webhooks", false, "Enable the validating and defaulting admission webhooks. Requires a TLS certificate to be provided for the webhook server.")
+	operatorUsername     = flag.String("operator-username", "", "The name of the user the operator authenticates as, e.g., system:serviceaccount:klyshko-system:klyshko-controller-manager. Jobs created by this user are not checked for their generator by the admission webhooks.")
 )
 
 func main() {
@@ -94,10 +103,12 @@ func main() {
 	castorClient := castor.NewClient(*castorURL)
 	if err = controllers.NewTupleGenerationJobReconciler(
 		mgr.GetClient(),
+		mgr.GetAPIReader(),
 		mgr.GetScheme(),
 		etcdClient,
 		castorClient,
-		mgr.GetLogger()).SetupWithManager(mgr); err != nil {
+		mgr.GetLogger(),
+		*maxConcurrentJobs).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TupleGenerationJob")
 		os.Exit(1)
 	}
@@ -113,14 +124,34 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.TupleGenerationSchedulerReconciler{
-		Client:       mgr.GetClient(),
-		Scheme:       mgr.GetScheme(),
-		CastorClient: castorClient,
-	}).SetupWithManager(mgr); err != nil {
+	registry := controllers.NewSchedulingStrategyRegistry()
+	if err = controllers.NewTupleGenerationSchedulerReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		etcdClient,
+		castorClient,
+		registry,
+		*drain).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TupleGenerationScheduler")
 		os.Exit(1)
 	}
+
+	if *gcInterval > 0 {
+		if err = controllers.NewGarbageCollector(
+			mgr.GetClient(),
+			etcdClient,
+			mgr.GetLogger().WithName("gc"),
+			*gcInterval,
+			*gcGracePeriod,
+			*gcDryRun).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create garbage collector")
+			os.Exit(1)
+		}
+	}
+
+	if *enableWebhooks {
+		controllers.SetupWebhooksWithManager(mgr, registry, *operatorUsername)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,188 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultStep is the default simulated time between two scheduling rounds. It matches the periodic reconciliation
+	// interval of the scheduler controller.
+	DefaultStep = 10 * time.Second
+
+	// DefaultStarvationThreshold is the default duration a tuple type may stay below its threshold without any active
+	// job before a starvation event is reported.
+	DefaultStarvationThreshold = 5 * time.Minute
+
+	// DefaultSampleInterval is the default simulated time between two samples of the fill levels.
+	DefaultSampleInterval = time.Minute
+)
+
+// TupleTypeConfig describes how tuples of a specific type are generated and consumed in a simulation.
+type TupleTypeConfig struct {
+
+	// Type is the tuple type.
+	Type string `json:"type"`
+
+	// Initial is the number of tuples available when the simulation starts.
+	Initial int `json:"initial,omitempty"`
+
+	// ConsumptionRate is the number of tuples consumed per second. It is superseded by the consumption rates of
+	// recorded telemetry, if given.
+	ConsumptionRate int `json:"consumptionRate,omitempty"`
+
+	// BatchSize is the number of tuples generated by a generator batch, i.e., the batch size of the generator.
+	BatchSize int `json:"batchSize"`
+
+	// Throughput is the number of tuples generated per second by a job.
+	Throughput float64 `json:"throughput"`
+
+	// SetupTime is the time it takes a job to start generating tuples, e.g., for pulling images and scheduling pods.
+	SetupTime metav1.Duration `json:"setupTime,omitempty"`
+}
+
+// Config describes a simulation run.
+type Config struct {
+
+	// Scheduler is the spec of the simulated scheduler.
+	Scheduler klyshkov1alpha1.TupleGenerationSchedulerSpec `json:"scheduler"`
+
+	// TupleTypes describe how tuples are generated and consumed per tuple type.
+	TupleTypes []TupleTypeConfig `json:"tupleTypes"`
+
+	// Start is the simulated point in time the simulation starts at. Relevant for the evaluation of generation windows
+	// and blackouts only. Defaults to the time of the first telemetry sample, if given, or the current time otherwise.
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// Duration is the simulated time span.
+	Duration metav1.Duration `json:"duration"`
+
+	// Step is the simulated time between two scheduling rounds.
+	Step metav1.Duration `json:"step,omitempty"`
+
+	// StarvationThreshold is the duration a tuple type may stay below its threshold without any active job before a
+	// starvation event is reported.
+	StarvationThreshold metav1.Duration `json:"starvationThreshold,omitempty"`
+
+	// SampleInterval is the simulated time between two samples of the fill levels.
+	SampleInterval metav1.Duration `json:"sampleInterval,omitempty"`
+}
+
+// TelemetrySample is a telemetry data set recorded from Castor at a specific point in time.
+type TelemetrySample struct {
+
+	// Time is the point in time the telemetry has been recorded at.
+	Time metav1.Time `json:"time"`
+
+	// Telemetry is the recorded telemetry.
+	Telemetry castor.Telemetry `json:"telemetry"`
+}
+
+// LoadConfig reads a simulation config in YAML or JSON format from the file with the given name. Defaults are applied
+// and the config is validated.
+func LoadConfig(name string) (*Config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read simulation config: %w", err)
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse simulation config: %w", err)
+	}
+	config.Default()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid simulation config: %w", err)
+	}
+	return config, nil
+}
+
+// LoadTelemetry reads a telemetry time series in YAML or JSON format from the file with the given name. Samples are
+// returned ordered by time.
+func LoadTelemetry(name string) ([]TelemetrySample, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read telemetry: %w", err)
+	}
+	var samples []TelemetrySample
+	if err := yaml.UnmarshalStrict(data, &samples); err != nil {
+		return nil, fmt.Errorf("failed to parse telemetry: %w", err)
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Time.Before(&samples[j].Time)
+	})
+	return samples, nil
+}
+
+// Default applies the defaults of the scheduler spec, as applied by the API server, and of the simulation itself.
+func (c *Config) Default() {
+	if c.Scheduler.Concurrency == 0 {
+		c.Scheduler.Concurrency = 1
+	}
+	if c.Scheduler.Strategy.Name == "" {
+		c.Scheduler.Strategy.Name = "Lottery"
+	}
+	for idx := range c.Scheduler.TupleTypePolicies {
+		if c.Scheduler.TupleTypePolicies[idx].Priority == 0 {
+			c.Scheduler.TupleTypePolicies[idx].Priority = 1
+		}
+	}
+	if c.Step.Duration == 0 {
+		c.Step.Duration = DefaultStep
+	}
+	if c.StarvationThreshold.Duration == 0 {
+		c.StarvationThreshold.Duration = DefaultStarvationThreshold
+	}
+	if c.SampleInterval.Duration == 0 {
+		c.SampleInterval.Duration = DefaultSampleInterval
+	}
+}
+
+// Validate checks that the config describes a simulation that can be run.
+func (c *Config) Validate() error {
+	if c.Duration.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+	if c.Step.Duration <= 0 {
+		return errors.New("step must be positive")
+	}
+	if c.SampleInterval.Duration < c.Step.Duration {
+		return errors.New("sample interval must not be shorter than the step")
+	}
+	if len(c.Scheduler.TupleTypePolicies) == 0 {
+		return errors.New("scheduler must declare at least a single tuple type policy")
+	}
+	tupleTypes := map[string]bool{}
+	for _, t := range c.TupleTypes {
+		if t.BatchSize <= 0 {
+			return fmt.Errorf("batch size of tuple type '%s' must be positive", t.Type)
+		}
+		if t.Throughput <= 0 {
+			return fmt.Errorf("throughput of tuple type '%s' must be positive", t.Type)
+		}
+		tupleTypes[t.Type] = true
+	}
+	for _, policy := range c.Scheduler.TupleTypePolicies {
+		if !tupleTypes[policy.Type] {
+			return fmt.Errorf("no tuple type config for policy of tuple type '%s'", policy.Type)
+		}
+		if policy.Priority < 1 {
+			return fmt.Errorf("priority of policy of tuple type '%s' must be positive", policy.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package simulation contains functionality for simulating the behavior of tuple generation schedulers offline, i.e.,
+// without a Kubernetes cluster, Castor service or tuple generators.
+package simulation
@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// WriteSummary writes a human-readable summary of the given simulation result to the given writer.
+func WriteSummary(w io.Writer, result *Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Simulated %s\n\n", result.Duration)
+	fmt.Fprintln(tw, "TYPE\tJOBS\tGENERATED\tCONSUMED\tUNSERVED\tMIN\tMAX\tEXHAUSTED\tEXHAUSTED FOR\tSTARVED\tSTARVED FOR")
+	for _, s := range result.Summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%s\t%d\t%s\n", s.Type, s.Jobs, s.Generated, s.Consumed,
+			s.Unserved, s.MinAvailable, s.MaxAvailable, s.ExhaustionEvents, s.ExhaustionTime, s.StarvationEvents,
+			s.StarvationTime)
+	}
+	return tw.Flush()
+}
+
+// WriteEvents writes the events of the given simulation result to the given writer, one per line.
+func WriteEvents(w io.Writer, result *Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tEVENT\tTYPE\tCOUNT")
+	for _, e := range result.Events {
+		count := ""
+		if e.Kind == JobStarted || e.Kind == JobFinished {
+			count = strconv.Itoa(e.Count)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Time, e.Kind, e.TupleType, count)
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes the fill levels sampled during the given simulation in CSV format to the given writer. Each row
+// contains the simulated time in seconds followed by the number of available and in-flight tuples and the number of
+// active jobs per tuple type.
+func WriteCSV(w io.Writer, result *Result) error {
+	writer := csv.NewWriter(w)
+	header := []string{"seconds"}
+	for _, s := range result.Summaries {
+		header = append(header, s.Type+".available", s.Type+".inFlight", s.Type+".activeJobs")
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, sample := range result.Samples {
+		row := []string{strconv.FormatInt(int64(sample.Time.Seconds()), 10)}
+		for _, t := range sample.TupleTypes {
+			row = append(row, strconv.Itoa(t.Available), strconv.Itoa(t.InFlight), strconv.Itoa(t.ActiveJobs))
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,482 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	"github.com/carbynestack/klyshko/controllers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EventKind is the kind of event that occurred during a simulation.
+type EventKind string
+
+const (
+	// JobStarted is emitted when the scheduler creates a job.
+	JobStarted EventKind = "JobStarted"
+
+	// JobFinished is emitted when a job completes and the tuples generated by it become available.
+	JobFinished EventKind = "JobFinished"
+
+	// Exhausted is emitted when the demand for tuples of a type can no longer be satisfied.
+	Exhausted EventKind = "Exhausted"
+
+	// Replenished is emitted when the demand for tuples of a type can be satisfied again after being exhausted.
+	Replenished EventKind = "Replenished"
+
+	// Starved is emitted when a tuple type stayed below its threshold without any active job for longer than the
+	// starvation threshold.
+	Starved EventKind = "Starved"
+)
+
+// Event is an event that occurred during a simulation.
+type Event struct {
+
+	// Time is the simulated time elapsed since the start of the simulation.
+	Time time.Duration
+
+	// Kind is the kind of event.
+	Kind EventKind
+
+	// TupleType is the tuple type the event relates to.
+	TupleType string
+
+	// Count is the number of tuples to be generated by the job in case of job events.
+	Count int
+}
+
+// TupleTypeSample is the fill level of a tuple type at a specific point in time.
+type TupleTypeSample struct {
+
+	// Type is the tuple type.
+	Type string
+
+	// Available is the number of tuples available.
+	Available int
+
+	// InFlight is the number of tuples being generated by active jobs.
+	InFlight int
+
+	// ActiveJobs is the number of active jobs.
+	ActiveJobs int
+}
+
+// Sample is the fill level of all tuple types at a specific point in time.
+type Sample struct {
+
+	// Time is the simulated time elapsed since the start of the simulation.
+	Time time.Duration
+
+	// TupleTypes are the fill levels per tuple type in the order of the tuple type configs.
+	TupleTypes []TupleTypeSample
+}
+
+// Summary aggregates the simulation results for a tuple type.
+type Summary struct {
+
+	// Type is the tuple type.
+	Type string
+
+	// Jobs is the number of jobs created.
+	Jobs int
+
+	// Generated is the number of tuples generated by finished jobs.
+	Generated int
+
+	// Consumed is the number of tuples consumed.
+	Consumed int
+
+	// Unserved is the number of tuples requested while the tuple type was exhausted.
+	Unserved int
+
+	// MinAvailable is the minimum number of tuples available.
+	MinAvailable int
+
+	// MaxAvailable is the maximum number of tuples available.
+	MaxAvailable int
+
+	// ExhaustionEvents is the number of times the tuple type got exhausted.
+	ExhaustionEvents int
+
+	// ExhaustionTime is the total time the demand for tuples could not be satisfied.
+	ExhaustionTime time.Duration
+
+	// StarvationEvents is the number of times the tuple type starved.
+	StarvationEvents int
+
+	// StarvationTime is the total time of all starvation episodes.
+	StarvationTime time.Duration
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+
+	// Duration is the simulated time span.
+	Duration time.Duration
+
+	// Samples are the fill levels sampled over time.
+	Samples []Sample
+
+	// Summaries are the aggregated results per tuple type in the order of the tuple type configs.
+	Summaries []Summary
+
+	// Events are the events that occurred during the simulation in chronological order.
+	Events []Event
+}
+
+// simulatedJob is a job running in a simulation.
+type simulatedJob struct {
+	job klyshkov1alpha1.TupleGenerationJob
+	end time.Duration
+}
+
+// tupleTypeState is the simulated state of a tuple type.
+type tupleTypeState struct {
+	config          TupleTypeConfig
+	available       int
+	consumptionRate int
+	demand          float64
+	exhausted       bool
+	belowSince      *time.Duration
+	starved         bool
+	summary         *Summary
+}
+
+// Simulator feeds recorded or synthetic telemetry into a scheduling strategy and simulates the jobs created by a
+// scheduler along with the consumption of tuples over time.
+//
+// Each step of the simulation resembles a reconciliation of the scheduler. The same effective policies are derived
+// and at most a single job is created per step. Jobs generate tuples at the configured throughput after the configured
+// setup time and never fail. A single generator is assumed per tuple type, i.e., generator selection and failure
+// handling are not simulated. Budgets are not enforced either.
+type Simulator struct {
+	config    Config
+	strategy  controllers.SchedulingStrategy
+	telemetry []TelemetrySample
+}
+
+// NewSimulator creates a Simulator for the given config using the given strategy. In case telemetry samples are
+// given, consumption rates are taken from the most recent sample at each point in time and the initial number of
+// available tuples is taken from the first sample. The samples must be ordered by time.
+func NewSimulator(config Config, strategy controllers.SchedulingStrategy, telemetry []TelemetrySample) *Simulator {
+	return &Simulator{
+		config:    config,
+		strategy:  strategy,
+		telemetry: telemetry,
+	}
+}
+
+// Run executes the simulation.
+func (s *Simulator) Run(ctx context.Context) (*Result, error) {
+	start := time.Now()
+	if s.config.Start != nil {
+		start = s.config.Start.Time
+	} else if len(s.telemetry) > 0 {
+		start = s.telemetry[0].Time.Time
+	}
+	if _, err := controllers.IsWindowOpen(s.config.Scheduler, start); err != nil {
+		return nil, fmt.Errorf("failed to evaluate generation windows: %w", err)
+	}
+
+	result := &Result{
+		Duration:  s.config.Duration.Duration,
+		Summaries: make([]Summary, len(s.config.TupleTypes)),
+	}
+	states := make([]*tupleTypeState, len(s.config.TupleTypes))
+	for idx, t := range s.config.TupleTypes {
+		result.Summaries[idx].Type = t.Type
+		states[idx] = &tupleTypeState{
+			config:          t,
+			available:       t.Initial,
+			consumptionRate: t.ConsumptionRate,
+			summary:         &result.Summaries[idx],
+		}
+	}
+	if len(s.telemetry) > 0 {
+		for _, state := range states {
+			if m := getTupleMetrics(s.telemetry[0].Telemetry, state.config.Type); m != nil {
+				state.available = m.Available
+			}
+		}
+	}
+	for _, state := range states {
+		state.summary.MinAvailable = state.available
+		state.summary.MaxAvailable = state.available
+	}
+
+	step := s.config.Step.Duration
+	var jobs []simulatedJob
+	var previous []klyshkov1alpha1.TupleTypeStatus
+	created := 0
+	nextSample := time.Duration(0)
+	sampleIdx := 0
+	for t := time.Duration(0); t <= s.config.Duration.Duration; t += step {
+		now := start.Add(t)
+
+		// Complete finished jobs
+		var active []simulatedJob
+		for _, j := range jobs {
+			if j.end > t {
+				active = append(active, j)
+				continue
+			}
+			state := getState(states, j.job.Spec.Type)
+			state.available += j.job.Spec.Count
+			state.summary.Generated += j.job.Spec.Count
+			result.Events = append(result.Events, Event{Time: t, Kind: JobFinished, TupleType: j.job.Spec.Type, Count: j.job.Spec.Count})
+		}
+		jobs = active
+
+		// Consume tuples requested since the previous step
+		if t > 0 {
+			for _, state := range states {
+				s.consume(state, t, result)
+			}
+		}
+
+		// Pick up consumption rates from the most recent telemetry sample
+		for sampleIdx < len(s.telemetry) && !s.telemetry[sampleIdx].Time.Time.After(now) {
+			for _, state := range states {
+				if m := getTupleMetrics(s.telemetry[sampleIdx].Telemetry, state.config.Type); m != nil {
+					state.consumptionRate = m.ConsumptionRate
+				}
+			}
+			sampleIdx++
+		}
+
+		// Create a job, if the scheduler decides to do so
+		if !s.config.Scheduler.Suspend && len(jobs) < s.getConcurrency(states) {
+			job, statuses, err := s.schedule(ctx, now, states, jobs, previous, created)
+			if err != nil {
+				return nil, err
+			}
+			previous = statuses
+			if job != nil {
+				created++
+				jobs = append(jobs, simulatedJob{job: *job, end: t + s.getJobDuration(getState(states, job.Spec.Type).config, job.Spec.Count)})
+				getState(states, job.Spec.Type).summary.Jobs++
+				result.Events = append(result.Events, Event{Time: t, Kind: JobStarted, TupleType: job.Spec.Type, Count: job.Spec.Count})
+			}
+		}
+
+		// Track starvation of tuple types
+		for _, policy := range s.config.Scheduler.TupleTypePolicies {
+			s.trackStarvation(getState(states, policy.Type), policy, jobs, t, result)
+		}
+
+		for _, state := range states {
+			if state.available < state.summary.MinAvailable {
+				state.summary.MinAvailable = state.available
+			}
+			if state.available > state.summary.MaxAvailable {
+				state.summary.MaxAvailable = state.available
+			}
+		}
+		if t >= nextSample {
+			result.Samples = append(result.Samples, newSample(t, states, jobs))
+			nextSample += s.config.SampleInterval.Duration
+		}
+	}
+
+	// Account for starvation episodes still ongoing at the end of the simulation
+	for _, state := range states {
+		if state.starved {
+			state.summary.StarvationTime += s.config.Duration.Duration - *state.belowSince
+		}
+	}
+	return result, nil
+}
+
+// consume simulates the consumption of tuples of the given type within the step ending at the given time.
+func (s *Simulator) consume(state *tupleTypeState, t time.Duration, result *Result) {
+	state.demand += float64(state.consumptionRate) * s.config.Step.Duration.Seconds()
+	requested := int(state.demand)
+	state.demand -= float64(requested)
+	consumed := requested
+	if consumed > state.available {
+		consumed = state.available
+	}
+	state.available -= consumed
+	state.summary.Consumed += consumed
+	state.summary.Unserved += requested - consumed
+	if requested > consumed {
+		state.summary.ExhaustionTime += s.config.Step.Duration
+		if !state.exhausted {
+			state.exhausted = true
+			state.summary.ExhaustionEvents++
+			result.Events = append(result.Events, Event{Time: t, Kind: Exhausted, TupleType: state.config.Type})
+		}
+	} else if state.exhausted && state.available > 0 {
+		state.exhausted = false
+		result.Events = append(result.Events, Event{Time: t, Kind: Replenished, TupleType: state.config.Type})
+	}
+}
+
+// schedule resembles a reconciliation of the scheduler at the given point in time. Returns the job created, if any,
+// along with the observed state per tuple type. The given ID is used to name the job.
+func (s *Simulator) schedule(ctx context.Context, now time.Time, states []*tupleTypeState, jobs []simulatedJob,
+	previous []klyshkov1alpha1.TupleTypeStatus, id int) (*klyshkov1alpha1.TupleGenerationJob, []klyshkov1alpha1.TupleTypeStatus, error) {
+	windowOpen, err := controllers.IsWindowOpen(s.config.Scheduler, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to evaluate generation windows: %w", err)
+	}
+	telemetry := castor.Telemetry{}
+	for _, state := range states {
+		telemetry.TupleMetrics = append(telemetry.TupleMetrics, castor.TupleMetrics{
+			Available:       state.available,
+			ConsumptionRate: state.consumptionRate,
+			TupleType:       state.config.Type,
+		})
+	}
+	activeJobs := make([]klyshkov1alpha1.TupleGenerationJob, 0, len(jobs))
+	withInflight := telemetry.DeepCopy()
+	for _, j := range jobs {
+		activeJobs = append(activeJobs, j.job)
+		if m := getTupleMetrics(withInflight, j.job.Spec.Type); m != nil {
+			m.Available += j.job.Spec.Count
+		}
+	}
+
+	policies, statuses := controllers.ApplyTupleTypePolicies(s.config.Scheduler.TupleTypePolicies, withInflight,
+		activeJobs, previous, windowOpen)
+	if len(jobs) >= s.config.Scheduler.Concurrency {
+		// Jobs beyond the regular concurrency are only created for tuple types below their emergency threshold
+		var emergency []klyshkov1alpha1.TupleTypePolicy
+		for _, policy := range policies {
+			for _, status := range statuses {
+				if status.Type == policy.Type && status.Emergency {
+					emergency = append(emergency, policy)
+				}
+			}
+		}
+		policies = emergency
+	}
+	tupleType, err := s.strategy.Schedule(ctx, telemetry, policies, activeJobs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scheduler strategy failed to decide on a tuple type: %w", err)
+	}
+	if tupleType == nil {
+		return nil, statuses, nil
+	}
+	var policy *klyshkov1alpha1.TupleTypePolicy
+	for idx := range policies {
+		if policies[idx].Type == *tupleType {
+			policy = &policies[idx]
+		}
+	}
+	if policy == nil {
+		return nil, nil, fmt.Errorf("scheduler strategy returned a tuple type without an associated policy: %s", *tupleType)
+	}
+	count := controllers.JobSize(*policy, getState(states, *tupleType).config.BatchSize,
+		getTupleMetrics(withInflight, *tupleType).Available)
+	return &klyshkov1alpha1.TupleGenerationJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("simulated-%d", id),
+		},
+		Spec: klyshkov1alpha1.TupleGenerationJobSpec{
+			ID:    fmt.Sprintf("simulated-%d", id),
+			Type:  *tupleType,
+			Count: count,
+		},
+	}, statuses, nil
+}
+
+// trackStarvation records starvation episodes of the given tuple type, i.e., periods in which the tuple type is below
+// its threshold without any active job for longer than the starvation threshold.
+func (s *Simulator) trackStarvation(state *tupleTypeState, policy klyshkov1alpha1.TupleTypePolicy, jobs []simulatedJob,
+	t time.Duration, result *Result) {
+	hasActiveJob := false
+	for _, j := range jobs {
+		if j.job.Spec.Type == policy.Type {
+			hasActiveJob = true
+			break
+		}
+	}
+	if state.available >= policy.Threshold || hasActiveJob {
+		if state.starved {
+			state.summary.StarvationTime += t - *state.belowSince
+		}
+		state.belowSince = nil
+		state.starved = false
+		return
+	}
+	if state.belowSince == nil {
+		since := t
+		state.belowSince = &since
+	}
+	if !state.starved && t-*state.belowSince >= s.config.StarvationThreshold.Duration {
+		state.starved = true
+		state.summary.StarvationEvents++
+		result.Events = append(result.Events, Event{Time: t, Kind: Starved, TupleType: policy.Type})
+	}
+}
+
+// getConcurrency returns the maximum number of active jobs. The regular concurrency of the scheduler may be exceeded up
+// to the burst concurrency while the number of available tuples of any tuple type is below its emergency threshold.
+func (s *Simulator) getConcurrency(states []*tupleTypeState) int {
+	spec := s.config.Scheduler
+	if spec.BurstConcurrency <= spec.Concurrency {
+		return spec.Concurrency
+	}
+	for _, policy := range spec.TupleTypePolicies {
+		if policy.EmergencyThreshold > 0 && getState(states, policy.Type).available < policy.EmergencyThreshold {
+			return spec.BurstConcurrency
+		}
+	}
+	return spec.Concurrency
+}
+
+// getJobDuration computes the time it takes a job to generate the given number of tuples.
+func (s *Simulator) getJobDuration(config TupleTypeConfig, count int) time.Duration {
+	return config.SetupTime.Duration + time.Duration(float64(count)/config.Throughput*float64(time.Second))
+}
+
+// newSample captures the fill levels of all tuple types.
+func newSample(t time.Duration, states []*tupleTypeState, jobs []simulatedJob) Sample {
+	sample := Sample{Time: t}
+	for _, state := range states {
+		tupleTypeSample := TupleTypeSample{
+			Type:      state.config.Type,
+			Available: state.available,
+		}
+		for _, j := range jobs {
+			if j.job.Spec.Type == state.config.Type {
+				tupleTypeSample.InFlight += j.job.Spec.Count
+				tupleTypeSample.ActiveJobs++
+			}
+		}
+		sample.TupleTypes = append(sample.TupleTypes, tupleTypeSample)
+	}
+	return sample
+}
+
+// getState returns the state of the given tuple type.
+func getState(states []*tupleTypeState, tupleType string) *tupleTypeState {
+	for _, state := range states {
+		if state.config.Type == tupleType {
+			return state
+		}
+	}
+	return nil
+}
+
+// getTupleMetrics returns the metrics of the given tuple type contained in the given telemetry, or nil if there are
+// none.
+func getTupleMetrics(telemetry castor.Telemetry, tupleType string) *castor.TupleMetrics {
+	for idx := range telemetry.TupleMetrics {
+		if telemetry.TupleMetrics[idx].TupleType == tupleType {
+			return &telemetry.TupleMetrics[idx]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright (c) 2026 - for information on the respective copyright owner
+see the NOTICE file and/or the repository https://github.com/carbynestack/klyshko.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulation
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	klyshkov1alpha1 "github.com/carbynestack/klyshko/api/v1alpha1"
+	"github.com/carbynestack/klyshko/castor"
+	"github.com/carbynestack/klyshko/controllers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestConfig creates a simulation config for a single tuple type generated by jobs taking a minute each.
+func newTestConfig(consumptionRate int) Config {
+	config := Config{
+		Scheduler: klyshkov1alpha1.TupleGenerationSchedulerSpec{
+			Strategy: klyshkov1alpha1.SchedulingStrategySpec{Name: controllers.PredictiveSchedulingStrategyName},
+			TupleTypePolicies: []klyshkov1alpha1.TupleTypePolicy{
+				{Type: "BIT_GFP", Threshold: 10000},
+			},
+		},
+		TupleTypes: []TupleTypeConfig{
+			{
+				Type:            "BIT_GFP",
+				Initial:         5000,
+				ConsumptionRate: consumptionRate,
+				BatchSize:       6000,
+				Throughput:      100,
+			},
+		},
+		Duration: metav1.Duration{Duration: time.Hour},
+	}
+	config.Default()
+	return config
+}
+
+// runSimulation runs a simulation for the given config using the strategy configured in the scheduler spec.
+func runSimulation(config Config, telemetry []TelemetrySample) *Result {
+	Expect(config.Validate()).To(Succeed())
+	strategy, err := controllers.NewSchedulingStrategyRegistry().New(config.Scheduler.Strategy)
+	Expect(err).NotTo(HaveOccurred())
+	result, err := NewSimulator(config, strategy, telemetry).Run(context.Background())
+	Expect(err).NotTo(HaveOccurred())
+	return result
+}
+
+var _ = Describe("Simulating a scheduler", func() {
+
+	When("generation keeps up with consumption", func() {
+		It("keeps tuples available", func() {
+			result := runSimulation(newTestConfig(50), nil)
+			summary := result.Summaries[0]
+			Expect(summary.Jobs).To(BeNumerically(">", 1))
+			Expect(summary.Generated % 6000).To(BeZero())
+			Expect(summary.Generated).To(BeNumerically("<=", summary.Jobs*6000))
+			Expect(summary.Consumed).To(Equal(50 * 3600))
+			Expect(summary.Unserved).To(BeZero())
+			Expect(summary.ExhaustionEvents).To(BeZero())
+			Expect(summary.StarvationEvents).To(BeZero())
+			Expect(summary.MinAvailable).To(BeNumerically(">", 0))
+		})
+	})
+
+	When("consumption exceeds the throughput of the scheduler", func() {
+		It("reports exhaustion", func() {
+			result := runSimulation(newTestConfig(200), nil)
+			summary := result.Summaries[0]
+			Expect(summary.ExhaustionEvents).To(BeNumerically(">=", 1))
+			Expect(summary.ExhaustionTime).To(BeNumerically(">", 0))
+			Expect(summary.Unserved).To(BeNumerically(">", 0))
+			Expect(summary.Consumed + summary.Unserved).To(Equal(200 * 3600))
+			Expect(summary.MinAvailable).To(BeZero())
+			Expect(result.Events).To(ContainElement(HaveField("Kind", Exhausted)))
+		})
+	})
+
+	When("the scheduler is suspended", func() {
+		It("reports starvation", func() {
+			config := newTestConfig(1)
+			config.Scheduler.Suspend = true
+			result := runSimulation(config, nil)
+			summary := result.Summaries[0]
+			Expect(summary.Jobs).To(BeZero())
+			Expect(summary.StarvationEvents).To(Equal(1))
+			Expect(summary.StarvationTime).To(Equal(time.Hour))
+			Expect(result.Events).To(ConsistOf(Event{Time: DefaultStarvationThreshold, Kind: Starved, TupleType: "BIT_GFP"}))
+		})
+	})
+
+	When("the concurrency allows for multiple jobs", func() {
+		It("runs jobs in parallel", func() {
+			config := newTestConfig(200)
+			config.Scheduler.Concurrency = 4
+			result := runSimulation(config, nil)
+			Expect(result.Samples).To(ContainElement(HaveField("TupleTypes", ContainElement(HaveField("ActiveJobs", 4)))))
+			Expect(result.Summaries[0].Unserved).To(BeNumerically("<", runSimulation(newTestConfig(200), nil).Summaries[0].Unserved))
+		})
+	})
+
+	When("burst mode is enabled", func() {
+		It("exceeds the concurrency while a tuple type is below its emergency threshold", func() {
+			config := newTestConfig(200)
+			config.Scheduler.BurstConcurrency = 4
+			config.Scheduler.TupleTypePolicies[0].EmergencyThreshold = 8000
+			result := runSimulation(config, nil)
+			Expect(result.Samples).To(ContainElement(HaveField("TupleTypes", ContainElement(HaveField("ActiveJobs", 2)))))
+			Expect(result.Summaries[0].Unserved).To(BeNumerically("<", runSimulation(newTestConfig(200), nil).Summaries[0].Unserved))
+		})
+	})
+
+	When("telemetry has been recorded", func() {
+		It("uses recorded availability and consumption rates", func() {
+			start := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			telemetry := []TelemetrySample{
+				{Time: start, Telemetry: castor.Telemetry{TupleMetrics: []castor.TupleMetrics{
+					{TupleType: "BIT_GFP", Available: 20000, ConsumptionRate: 0},
+				}}},
+				{Time: metav1.NewTime(start.Add(30 * time.Minute)), Telemetry: castor.Telemetry{TupleMetrics: []castor.TupleMetrics{
+					{TupleType: "BIT_GFP", Available: 0, ConsumptionRate: 10},
+				}}},
+			}
+			result := runSimulation(newTestConfig(50), telemetry)
+			Expect(result.Samples[0].TupleTypes[0].Available).To(Equal(20000))
+			Expect(result.Summaries[0].Consumed).To(Equal(10 * 1800))
+		})
+	})
+
+	It("writes sampled fill levels as CSV", func() {
+		result := runSimulation(newTestConfig(50), nil)
+		var buf bytes.Buffer
+		Expect(WriteCSV(&buf, result)).To(Succeed())
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(len(result.Samples) + 1))
+		Expect(lines[0]).To(Equal("seconds,BIT_GFP.available,BIT_GFP.inFlight,BIT_GFP.activeJobs"))
+		Expect(lines[1]).To(Equal("0,5000,6000,1"))
+	})
+})
+
+var _ = Describe("Loading a simulation config", func() {
+
+	It("applies defaults", func() {
+		name := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(name, []byte(`
+scheduler:
+  policies:
+  - type: BIT_GFP
+    threshold: 10000
+tupleTypes:
+- type: BIT_GFP
+  batchSize: 1000
+  throughput: 100
+duration: 1h
+`), 0600)).To(Succeed())
+		config, err := LoadConfig(name)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Step.Duration).To(Equal(DefaultStep))
+		Expect(config.Scheduler.Concurrency).To(Equal(1))
+		Expect(config.Scheduler.TupleTypePolicies[0].Priority).To(Equal(1))
+	})
+
+	It("fails for policies without tuple type config", func() {
+		config := newTestConfig(50)
+		config.Scheduler.TupleTypePolicies = append(config.Scheduler.TupleTypePolicies,
+			klyshkov1alpha1.TupleTypePolicy{Type: "INPUT_MASK_GFP", Threshold: 1})
+		Expect(config.Validate()).To(MatchError(ContainSubstring("INPUT_MASK_GFP")))
+	})
+
+	It("fails for policies without positive priority", func() {
+		config := newTestConfig(50)
+		config.Scheduler.TupleTypePolicies[0].Priority = -1
+		Expect(config.Validate()).To(MatchError(ContainSubstring("must be positive")))
+	})
+})